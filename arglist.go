@@ -0,0 +1,355 @@
+package sllm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ArgList is a list of positional arguments that keeps the arguments with
+// their types, i.e. without boxing them into interfaces. ArgLists are pooled:
+// Get one with NewArgList and return it with Free when the message has been
+// rendered:
+//
+//	args := sllm.NewArgList().Int(7).Str("Hat").Str("John Doe")
+//	buf, err = args.Append(buf, tmpl)
+//	args.Free()
+//
+// Rendering a message with an ArgList does not allocate for ints, uints,
+// floats, bools, strings, time.Time and time.Duration. For a fixed number of
+// arguments with types known at compile time, the generic [Args1] … [Args5]
+// need no pool.
+type ArgList struct {
+	args []typedArg
+}
+
+type argKind uint8
+
+const (
+	kindAny argKind = iota
+	kindInt
+	kindUint
+	kindFloat
+	kindBool
+	kindStr
+	kindTime
+	kindDur
+)
+
+type typedArg struct {
+	kind argKind
+	num  uint64 // int, uint, float bits, bool and duration
+	str  string
+	t    time.Time
+	v    any
+}
+
+var argListPool = sync.Pool{
+	New: func() any { return &ArgList{args: make([]typedArg, 0, 8)} },
+}
+
+// NewArgList returns an empty ArgList from the pool.
+func NewArgList() *ArgList { return argListPool.Get().(*ArgList) }
+
+// Free resets l and puts it back into the pool. l must not be used after
+// calling Free.
+func (l *ArgList) Free() {
+	l.Reset()
+	argListPool.Put(l)
+}
+
+// Reset removes all arguments from l.
+func (l *ArgList) Reset() {
+	clear(l.args)
+	l.args = l.args[:0]
+}
+
+// Len returns the number of arguments in l.
+func (l *ArgList) Len() int { return len(l.args) }
+
+// Int adds the int i.
+func (l *ArgList) Int(i int) *ArgList { return l.Int64(int64(i)) }
+
+// Int64 adds the int64 i.
+func (l *ArgList) Int64(i int64) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindInt, num: uint64(i)})
+	return l
+}
+
+// Uint adds the uint u.
+func (l *ArgList) Uint(u uint) *ArgList { return l.Uint64(uint64(u)) }
+
+// Uint64 adds the uint64 u.
+func (l *ArgList) Uint64(u uint64) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindUint, num: u})
+	return l
+}
+
+// Float adds the float64 f.
+func (l *ArgList) Float(f float64) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindFloat, num: math.Float64bits(f)})
+	return l
+}
+
+// Bool adds the bool b.
+func (l *ArgList) Bool(b bool) *ArgList {
+	a := typedArg{kind: kindBool}
+	if b {
+		a.num = 1
+	}
+	l.args = append(l.args, a)
+	return l
+}
+
+// Str adds the string s.
+func (l *ArgList) Str(s string) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindStr, str: s})
+	return l
+}
+
+// Time adds t that is appended with [DefaultTimeFormat].
+func (l *ArgList) Time(t time.Time) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindTime, t: t})
+	return l
+}
+
+// Dur adds the duration d.
+func (l *ArgList) Dur(d time.Duration) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindDur, num: uint64(d)})
+	return l
+}
+
+// Any adds v that is appended with [AppendArg].
+func (l *ArgList) Any(v any) *ArgList {
+	l.args = append(l.args, typedArg{kind: kindAny, v: v})
+	return l
+}
+
+// Arg is the [ArgsFunc] of l. It appends the argument with index i.
+func (l *ArgList) Arg(buf []byte, i int, n string) ([]byte, error) {
+	if i < 0 || i >= len(l.args) {
+		return missingArg(buf, i, n)
+	}
+	switch a := &l.args[i]; a.kind {
+	case kindInt:
+		return strconv.AppendInt(buf, int64(a.num), 10), nil
+	case kindUint:
+		return strconv.AppendUint(buf, a.num, 10), nil
+	case kindFloat:
+		return strconv.AppendFloat(buf, math.Float64frombits(a.num), 'f', -1, 64), nil
+	case kindBool:
+		return strconv.AppendBool(buf, a.num != 0), nil
+	case kindStr:
+		return EscString(buf, a.str), nil
+	case kindTime:
+		return DefaultTimeFormat.Append(buf, a.t), nil
+	case kindDur:
+		return appendDuration(buf, time.Duration(a.num)), nil
+	default:
+		return AppendArg(buf, a.v), nil
+	}
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// l to the buffer to.
+func (l *ArgList) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, l.Arg)
+}
+
+// appendTyped appends *v without boxing it for the types AppendArg appends
+// without allocation. All other types are passed to AppendArg.
+func appendTyped[T any](to []byte, v *T) []byte {
+	switch a := any(v).(type) {
+	case *string:
+		return EscString(to, *a)
+	case *[]byte:
+		return EscBytes(to, *a)
+	case *int:
+		return strconv.AppendInt(to, int64(*a), 10)
+	case *int64:
+		return strconv.AppendInt(to, *a, 10)
+	case *int32:
+		return strconv.AppendInt(to, int64(*a), 10)
+	case *int16:
+		return strconv.AppendInt(to, int64(*a), 10)
+	case *int8:
+		return strconv.AppendInt(to, int64(*a), 10)
+	case *uint:
+		return strconv.AppendUint(to, uint64(*a), 10)
+	case *uint64:
+		return strconv.AppendUint(to, *a, 10)
+	case *uint32:
+		return strconv.AppendUint(to, uint64(*a), 10)
+	case *uint16:
+		return strconv.AppendUint(to, uint64(*a), 10)
+	case *uint8:
+		return strconv.AppendUint(to, uint64(*a), 10)
+	case *float64:
+		return strconv.AppendFloat(to, *a, 'f', -1, 64)
+	case *float32:
+		return strconv.AppendFloat(to, float64(*a), 'f', -1, 32)
+	case *bool:
+		return strconv.AppendBool(to, *a)
+	case *time.Time:
+		return DefaultTimeFormat.Append(to, *a)
+	case *time.Duration:
+		return appendDuration(to, *a)
+	}
+	return AppendArg(to, *v)
+}
+
+func missingArg(buf []byte, i int, n string) ([]byte, error) {
+	return buf, fmt.Errorf("missing argument %d '%s'", i, n)
+}
+
+// Args1 is a positional argument of type A that is appended without boxing
+// it into an interface. Args1 and its siblings up to [Args5] need no
+// allocation for the types that [ArgList] supports.
+type Args1[A any] struct{ V0 A }
+
+// NewArgs1 returns Args1 with the arguments a.
+func NewArgs1[A any](a A) Args1[A] { return Args1[A]{a} }
+
+// Arg is the [ArgsFunc] of a.
+func (a *Args1[A]) Arg(buf []byte, i int, n string) ([]byte, error) {
+	if i == 0 {
+		return appendTyped(buf, &a.V0), nil
+	}
+	return missingArg(buf, i, n)
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// a to the buffer to.
+func (a *Args1[A]) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, a.Arg)
+}
+
+// Args2 is like [Args1] for two arguments.
+type Args2[A, B any] struct {
+	V0 A
+	V1 B
+}
+
+// NewArgs2 returns Args2 with the arguments a and b.
+func NewArgs2[A, B any](a A, b B) Args2[A, B] { return Args2[A, B]{a, b} }
+
+// Arg is the [ArgsFunc] of a.
+func (a *Args2[A, B]) Arg(buf []byte, i int, n string) ([]byte, error) {
+	switch i {
+	case 0:
+		return appendTyped(buf, &a.V0), nil
+	case 1:
+		return appendTyped(buf, &a.V1), nil
+	}
+	return missingArg(buf, i, n)
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// a to the buffer to.
+func (a *Args2[A, B]) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, a.Arg)
+}
+
+// Args3 is like [Args1] for three arguments.
+type Args3[A, B, C any] struct {
+	V0 A
+	V1 B
+	V2 C
+}
+
+// NewArgs3 returns Args3 with the arguments a, b and c.
+func NewArgs3[A, B, C any](a A, b B, c C) Args3[A, B, C] {
+	return Args3[A, B, C]{a, b, c}
+}
+
+// Arg is the [ArgsFunc] of a.
+func (a *Args3[A, B, C]) Arg(buf []byte, i int, n string) ([]byte, error) {
+	switch i {
+	case 0:
+		return appendTyped(buf, &a.V0), nil
+	case 1:
+		return appendTyped(buf, &a.V1), nil
+	case 2:
+		return appendTyped(buf, &a.V2), nil
+	}
+	return missingArg(buf, i, n)
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// a to the buffer to.
+func (a *Args3[A, B, C]) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, a.Arg)
+}
+
+// Args4 is like [Args1] for four arguments.
+type Args4[A, B, C, D any] struct {
+	V0 A
+	V1 B
+	V2 C
+	V3 D
+}
+
+// NewArgs4 returns Args4 with the arguments a, b, c and d.
+func NewArgs4[A, B, C, D any](a A, b B, c C, d D) Args4[A, B, C, D] {
+	return Args4[A, B, C, D]{a, b, c, d}
+}
+
+// Arg is the [ArgsFunc] of a.
+func (a *Args4[A, B, C, D]) Arg(buf []byte, i int, n string) ([]byte, error) {
+	switch i {
+	case 0:
+		return appendTyped(buf, &a.V0), nil
+	case 1:
+		return appendTyped(buf, &a.V1), nil
+	case 2:
+		return appendTyped(buf, &a.V2), nil
+	case 3:
+		return appendTyped(buf, &a.V3), nil
+	}
+	return missingArg(buf, i, n)
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// a to the buffer to.
+func (a *Args4[A, B, C, D]) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, a.Arg)
+}
+
+// Args5 is like [Args1] for five arguments.
+type Args5[A, B, C, D, E any] struct {
+	V0 A
+	V1 B
+	V2 C
+	V3 D
+	V4 E
+}
+
+// NewArgs5 returns Args5 with the arguments a, b, c, d and e.
+func NewArgs5[A, B, C, D, E any](a A, b B, c C, d D, e E) Args5[A, B, C, D, E] {
+	return Args5[A, B, C, D, E]{a, b, c, d, e}
+}
+
+// Arg is the [ArgsFunc] of a.
+func (a *Args5[A, B, C, D, E]) Arg(buf []byte, i int, n string) ([]byte, error) {
+	switch i {
+	case 0:
+		return appendTyped(buf, &a.V0), nil
+	case 1:
+		return appendTyped(buf, &a.V1), nil
+	case 2:
+		return appendTyped(buf, &a.V2), nil
+	case 3:
+		return appendTyped(buf, &a.V3), nil
+	case 4:
+		return appendTyped(buf, &a.V4), nil
+	}
+	return missingArg(buf, i, n)
+}
+
+// Append appends the message from the template tmpl with the arguments from
+// a to the buffer to.
+func (a *Args5[A, B, C, D, E]) Append(to []byte, tmpl string) ([]byte, error) {
+	return Append(to, tmpl, a.Arg)
+}
@@ -0,0 +1,103 @@
+package sllm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func ExampleArgList() {
+	args := NewArgList().Int(7).Str("Hat").Str("John Doe")
+	defer args.Free()
+	buf, _ := args.Append(nil, "added `count` x `item` to shopping cart by `user`")
+	fmt.Println(string(buf))
+	// Output:
+	// added `count:7` x `item:Hat` to shopping cart by `user:John Doe`
+}
+
+func TestArgList(t *testing.T) {
+	args := NewArgList().
+		Int(-1).Uint64(2).Float(0.5).Bool(true).Str("a`b").
+		Time(argTime).Dur(argDur).Any([]int{1})
+	defer args.Free()
+	buf, err := args.Append(nil, "`i` `u` `f` `b` `s` `t` `d` `a` `x`")
+	const expect = "`i:-1` `u:2` `f:0.5` `b:true` `s:a``b` " +
+		"`t:2023-11-27 Mo 21:30:00.123456+00` `d:1h30m0s` `a:[1]` " +
+		"`x!(missing argument 8 'x')`"
+	if s := string(buf); s != expect {
+		t.Errorf("unexpected message '%s'", s)
+	}
+	if err == nil {
+		t.Error("no error for missing argument")
+	}
+}
+
+func ExampleArgs3() {
+	args := NewArgs3(7, "Hat", "John Doe")
+	buf, _ := args.Append(nil, "added `count` x `item` to shopping cart by `user`")
+	fmt.Println(string(buf))
+	// Output:
+	// added `count:7` x `item:Hat` to shopping cart by `user:John Doe`
+}
+
+func TestArgs5(t *testing.T) {
+	args := NewArgs5(int8(-1), uint16(2), float32(0.5), argDur, []int{1})
+	buf, err := Append(nil, "`a` `b` `c` `d` `e` `f`", args.Arg)
+	const expect = "`a:-1` `b:2` `c:0.5` `d:1h30m0s` `e:[1]` `f!(missing argument 5 'f')`"
+	if s := string(buf); s != expect {
+		t.Errorf("unexpected message '%s'", s)
+	}
+	if err == nil {
+		t.Error("no error for missing argument")
+	}
+}
+
+func TestArgs5_allocs(t *testing.T) {
+	const tmpl = "`service`: Sent `signal` to main `process` (`name`) at `at`."
+	buf := make([]byte, 0, 256)
+	now := time.Now()
+	allocs := testing.AllocsPerRun(100, func() {
+		args := NewArgs5(testSvc, testSig, testProc, testName, now)
+		buf, _ = args.Append(buf[:0], tmpl)
+	})
+	if allocs != 0 {
+		t.Errorf("%.1f allocations", allocs)
+	}
+}
+
+func TestArgList_allocs(t *testing.T) {
+	const tmpl = "`service`: Sent `signal` to main `process` (`name`) at `at`."
+	buf := make([]byte, 0, 256)
+	now := time.Now()
+	allocs := testing.AllocsPerRun(100, func() {
+		args := NewArgList().Str(testSvc).Str(testSig).Int(testProc).Str(testName).Time(now)
+		buf, _ = args.Append(buf[:0], tmpl)
+		args.Free()
+	})
+	if allocs != 0 {
+		t.Errorf("%.1f allocations", allocs)
+	}
+}
+
+func BenchmarkArgList(b *testing.B) {
+	const tmpl = "`service`: Sent `signal` to main `process` (`name`) at `at`."
+	buf := make([]byte, 0, 256)
+	now := time.Now()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		args := NewArgList().Str(testSvc).Str(testSig).Int(testProc).Str(testName).Time(now)
+		buf, _ = args.Append(buf[:0], tmpl)
+		args.Free()
+	}
+}
+
+func BenchmarkArgs5(b *testing.B) {
+	const tmpl = "`service`: Sent `signal` to main `process` (`name`) at `at`."
+	buf := make([]byte, 0, 256)
+	now := time.Now()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		args := NewArgs5(testSvc, testSig, testProc, testName, now)
+		buf, _ = args.Append(buf[:0], tmpl)
+	}
+}
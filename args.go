@@ -2,9 +2,14 @@ package sllm
 
 import (
 	"bytes"
+	"encoding"
 	"fmt"
+	"net"
+	"net/netip"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Appender interface {
@@ -12,51 +17,110 @@ type Appender interface {
 }
 
 func IdxArgs(args ...any) func([]byte, int, string) ([]byte, error) {
+	return idxArgs(nil, args)
+}
+
+func IdxArgsDefault(d any, args ...any) func([]byte, int, string) ([]byte, error) {
+	return idxArgsDefault(nil, d, args)
+}
+
+func NmArgs(args map[string]any) func([]byte, int, string) ([]byte, error) {
+	return nmArgs(nil, args)
+}
+
+func NmArgsDefault(d any, args map[string]any) func([]byte, int, string) ([]byte, error) {
+	return nmArgsDefault(nil, d, args)
+}
+
+// IdxValues returns a ValuesFunc for the positional arguments args. Unlike
+// IdxArgs, format specs are applied to the typed arguments, see
+// [AppendValues].
+func IdxValues(args ...any) ValuesFunc {
+	return func(i int, n string) (any, error) {
+		if i < 0 || i >= len(args) {
+			return nil, fmt.Errorf("missing argument %d '%s'", i, n)
+		}
+		return args[i], nil
+	}
+}
+
+// NmValues returns a ValuesFunc for the named arguments args. Unlike NmArgs,
+// format specs are applied to the typed arguments, see [AppendValues].
+func NmValues(args map[string]any) ValuesFunc {
+	return func(i int, n string) (any, error) {
+		if a, ok := args[n]; ok {
+			return a, nil
+		}
+		return nil, fmt.Errorf("missing argument %d '%s'", i, n)
+	}
+}
+
+func idxArgs(r *Renderers, args []any) ArgsFunc {
 	return func(buf []byte, i int, n string) ([]byte, error) {
 		if i < 0 || i >= len(args) {
 			return buf, fmt.Errorf("missing argument %d '%s'", i, n)
 		}
-		return AppendArg(buf, args[i]), nil
+		return appendArg(buf, args[i], r), nil
 	}
 }
 
-func IdxArgsDefault(d any, args ...any) func([]byte, int, string) ([]byte, error) {
+func idxArgsDefault(r *Renderers, d any, args []any) ArgsFunc {
 	return func(buf []byte, i int, n string) ([]byte, error) {
 		if i < 0 || i >= len(args) {
-			return AppendArg(buf, d), nil
+			return appendArg(buf, d, r), nil
 		}
-		return AppendArg(buf, args[i]), nil
+		return appendArg(buf, args[i], r), nil
 	}
 }
 
-func NmArgs(args map[string]any) func([]byte, int, string) ([]byte, error) {
+func nmArgs(r *Renderers, args map[string]any) ArgsFunc {
 	return func(buf []byte, i int, n string) ([]byte, error) {
 		if a, ok := args[n]; ok {
-			return AppendArg(buf, a), nil
+			return appendArg(buf, a, r), nil
 		}
 		return buf, fmt.Errorf("missing argument %d '%s'", i, n)
 	}
 }
 
-func NmArgsDefault(d any, args map[string]any) func([]byte, int, string) ([]byte, error) {
+func nmArgsDefault(r *Renderers, d any, args map[string]any) ArgsFunc {
 	return func(buf []byte, i int, n string) ([]byte, error) {
 		if a, ok := args[n]; ok {
-			return AppendArg(buf, a), nil
+			return appendArg(buf, a, r), nil
 		}
-		return AppendArg(buf, d), nil
+		return appendArg(buf, d, r), nil
 	}
 }
 
-func AppendArg(to []byte, v any) []byte {
+// DefaultTimeFormat is used by [AppendArg] to append time.Time values.
+var DefaultTimeFormat = TYear | TMicros
+
+// AppendArg appends the escaped text of v to the buffer to. Besides
+// [Appender], strings, []byte, bools, all int, uint and float types,
+// time.Time (using [DefaultTimeFormat]), time.Duration, net.IP, netip.Addr,
+// nil and pointers to such values are appended without allocation. For [Lazy]
+// values and funcs of type func() any, the result of calling them is
+// appended. Other values are appended with [DefaultRenderers] or as error,
+// [fmt.Stringer], [encoding.TextMarshaler] or with fmt.Sprint, in this order.
+func AppendArg(to []byte, v any) []byte { return appendArg(to, v, nil) }
+
+func appendArg(to []byte, v any, r *Renderers) []byte {
 	switch a := v.(type) {
 	case Appender:
 		return a.AppendSllm(to)
 	case string:
 		return EscString(to, a)
+	case []byte:
+		return EscBytes(to, a)
 	case int:
 		return strconv.AppendInt(to, int64(a), 10)
 	case int64:
 		return strconv.AppendInt(to, a, 10)
+	case int32:
+		return strconv.AppendInt(to, int64(a), 10)
+	case int16:
+		return strconv.AppendInt(to, int64(a), 10)
+	case int8:
+		return strconv.AppendInt(to, int64(a), 10)
 	case bool:
 		return strconv.AppendBool(to, a)
 	case float64:
@@ -67,10 +131,177 @@ func AppendArg(to []byte, v any) []byte {
 		return strconv.AppendUint(to, uint64(a), 10)
 	case uint64:
 		return strconv.AppendUint(to, a, 10)
+	case uint32:
+		return strconv.AppendUint(to, uint64(a), 10)
+	case uint16:
+		return strconv.AppendUint(to, uint64(a), 10)
+	case uint8:
+		return strconv.AppendUint(to, uint64(a), 10)
+	case uintptr:
+		return strconv.AppendUint(to, uint64(a), 10)
+	case time.Time:
+		return DefaultTimeFormat.Append(to, a)
+	case *time.Time:
+		if a == nil {
+			return append(to, nilArg...)
+		}
+		return DefaultTimeFormat.Append(to, *a)
+	case time.Duration:
+		return appendDuration(to, a)
+	case *time.Duration:
+		if a == nil {
+			return append(to, nilArg...)
+		}
+		return appendDuration(to, *a)
+	case netip.Addr:
+		return a.AppendTo(to)
+	case net.IP:
+		return appendIP(to, a)
+	case Lazy:
+		return appendLazy(to, a, r)
+	case func() any:
+		return appendLazy(to, a, r)
+	case nil:
+		return append(to, nilArg...)
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && rv.IsNil() {
+		return append(to, nilArg...)
+	}
+	if r != nil {
+		if res, ok := r.Append(to, v); ok {
+			return res
+		}
+	}
+	if res, ok := DefaultRenderers.Append(to, v); ok {
+		return res
+	}
+	switch a := v.(type) {
+	case error:
+		return EscString(to, a.Error())
 	case fmt.Stringer:
 		return EscString(to, a.String())
-	default:
-		return EscString(to, fmt.Sprint(a))
+	case encoding.TextMarshaler:
+		if txt, err := a.MarshalText(); err == nil {
+			return EscBytes(to, txt)
+		}
+	}
+	if rv.Kind() == reflect.Pointer {
+		switch e := rv.Elem(); e.Kind() {
+		case reflect.String:
+			return EscString(to, e.String())
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return strconv.AppendInt(to, e.Int(), 10)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			return strconv.AppendUint(to, e.Uint(), 10)
+		case reflect.Float32, reflect.Float64:
+			return strconv.AppendFloat(to, e.Float(), 'f', -1, e.Type().Bits())
+		case reflect.Bool:
+			return strconv.AppendBool(to, e.Bool())
+		default:
+			if e.CanInterface() {
+				return appendArg(to, e.Interface(), r)
+			}
+		}
+	}
+	return EscString(to, fmt.Sprint(v))
+}
+
+const nilArg = "<nil>"
+
+func appendIP(to []byte, ip net.IP) []byte {
+	if ip4 := ip.To4(); ip4 != nil {
+		return netip.AddrFrom4([4]byte(ip4)).AppendTo(to)
+	}
+	if a, ok := netip.AddrFromSlice(ip); ok {
+		return a.AppendTo(to)
+	}
+	if len(ip) == 0 {
+		return append(to, nilArg...)
+	}
+	return EscString(to, ip.String())
+}
+
+// appendDuration appends d in the format of time.Duration.String without
+// allocation.
+func appendDuration(to []byte, d time.Duration) []byte {
+	var buf [32]byte
+	w := len(buf)
+	u := uint64(d)
+	if d < 0 {
+		u = -u
+	}
+	if u < uint64(time.Second) {
+		var prec int
+		w--
+		buf[w] = 's'
+		w--
+		switch {
+		case u == 0:
+			return append(to, "0s"...)
+		case u < uint64(time.Microsecond):
+			buf[w] = 'n'
+		case u < uint64(time.Millisecond):
+			prec = 3
+			w-- // 'µ' needs two bytes
+			copy(buf[w:], "µ")
+		default:
+			prec = 6
+			buf[w] = 'm'
+		}
+		w, u = durationFrac(buf[:w], u, prec)
+		w = durationInt(buf[:w], u)
+	} else {
+		w--
+		buf[w] = 's'
+		w, u = durationFrac(buf[:w], u, 9)
+		w = durationInt(buf[:w], u%60)
+		if u /= 60; u > 0 {
+			w--
+			buf[w] = 'm'
+			w = durationInt(buf[:w], u%60)
+			if u /= 60; u > 0 {
+				w--
+				buf[w] = 'h'
+				w = durationInt(buf[:w], u)
+			}
+		}
+	}
+	if d < 0 {
+		w--
+		buf[w] = '-'
+	}
+	return append(to, buf[w:]...)
+}
+
+// durationFrac writes the fraction of v/10^prec to the end of buf, omitting
+// trailing zeros. It returns the start index of the fraction and v/10^prec.
+func durationFrac(buf []byte, v uint64, prec int) (int, uint64) {
+	w, digits := len(buf), false
+	for range prec {
+		d := v % 10
+		if digits = digits || d != 0; digits {
+			w--
+			buf[w] = byte(d) + '0'
+		}
+		v /= 10
+	}
+	if digits {
+		w--
+		buf[w] = '.'
+	}
+	return w, v
+}
+
+// durationInt writes v to the end of buf and returns the start index.
+func durationInt(buf []byte, v uint64) int {
+	w := len(buf)
+	for {
+		w--
+		buf[w] = byte(v%10) + '0'
+		if v /= 10; v == 0 {
+			return w
+		}
 	}
 }
 
@@ -84,6 +315,15 @@ func EscString(to []byte, val string) []byte {
 	return append(to, val...)
 }
 
+// UnescString reverts the escaping of EscString, i.e. it replaces each double
+// backtick in val with a single one.
+func UnescString(val string) string {
+	if strings.IndexByte(val, '`') < 0 {
+		return val
+	}
+	return strings.ReplaceAll(val, "``", "`")
+}
+
 func EscBytes(to, val []byte) []byte {
 	for tic := bytes.IndexByte(val, '`'); tic >= 0; tic = bytes.IndexByte(val, '`') {
 		tic++
@@ -1 +1,122 @@
 package sllm
+
+import (
+	"errors"
+	"maps"
+	"math"
+	"net"
+	"net/netip"
+	"slices"
+	"testing"
+	"time"
+)
+
+type textArg struct{ s string }
+
+func (t textArg) MarshalText() ([]byte, error) { return []byte(t.s), nil }
+
+type strArg struct{}
+
+func (*strArg) String() string { return "str" }
+
+var (
+	argStr  = "a`b"
+	argInt  = 4711
+	argTime = time.Date(2023, 11, 27, 21, 30, 0, 123456789, time.UTC)
+	argDur  = 90 * time.Minute
+	argIP   = net.ParseIP("192.168.1.7")
+	argAddr = netip.MustParseAddr("2001:db8::1")
+)
+
+func TestAppendArg(t *testing.T) {
+	tests := []struct {
+		arg    any
+		expect string
+	}{
+		{int8(-8), "-8"},
+		{int16(-16), "-16"},
+		{int32(-32), "-32"},
+		{uint8(8), "8"},
+		{uint16(16), "16"},
+		{uint32(32), "32"},
+		{uintptr(64), "64"},
+		{[]byte("x`y"), "x``y"},
+		{argTime, "2023-11-27 Mo 21:30:00.123456+00"},
+		{&argTime, "2023-11-27 Mo 21:30:00.123456+00"},
+		{(*time.Time)(nil), "<nil>"},
+		{argDur, "1h30m0s"},
+		{&argDur, "1h30m0s"},
+		{argIP, "192.168.1.7"},
+		{net.ParseIP("2001:db8::2"), "2001:db8::2"},
+		{net.IP(nil), "<nil>"},
+		{argAddr, "2001:db8::1"},
+		{errors.New("fail`ed"), "fail``ed"},
+		{textArg{"txt`"}, "txt``"},
+		{&strArg{}, "str"},
+		{(*strArg)(nil), "<nil>"},
+		{nil, "<nil>"},
+		{&argStr, "a``b"},
+		{&argInt, "4711"},
+		{(*int)(nil), "<nil>"},
+		{[]int{1, 2}, "[1 2]"},
+	}
+	for _, test := range tests {
+		if s := string(AppendArg(nil, test.arg)); s != test.expect {
+			t.Errorf("%T: expect '%s', got '%s'", test.arg, test.expect, s)
+		}
+	}
+}
+
+func TestAppendDuration(t *testing.T) {
+	for _, d := range []time.Duration{
+		0, 1, -1, 999, 1000, 1500, 999999, 1000000, 1234567, 999999999,
+		time.Second, -time.Second, 1500 * time.Millisecond,
+		61 * time.Second, time.Hour + time.Nanosecond,
+		math.MaxInt64, math.MinInt64,
+	} {
+		if s := string(appendDuration(nil, d)); s != d.String() {
+			t.Errorf("expect '%s', got '%s'", d, s)
+		}
+	}
+}
+
+var zeroAllocArgs = map[string]any{
+	"int8":     int8(-8),
+	"uint16":   uint16(16),
+	"uintptr":  uintptr(64),
+	"bytes":    []byte("x`y"),
+	"time":     argTime,
+	"duration": argDur,
+	"ip":       argIP,
+	"addr":     argAddr,
+	"nil":      nil,
+	"nil ptr":  (*time.Time)(nil),
+	"ptr str":  &argStr,
+	"ptr int":  &argInt,
+	"ptr time": &argTime,
+}
+
+func TestAppendArg_allocs(t *testing.T) {
+	buf := make([]byte, 0, 256)
+	for name, arg := range zeroAllocArgs {
+		allocs := testing.AllocsPerRun(100, func() {
+			buf = AppendArg(buf[:0], arg)
+		})
+		if allocs != 0 {
+			t.Errorf("%s: %.1f allocations", name, allocs)
+		}
+	}
+}
+
+func BenchmarkAppendArg(b *testing.B) {
+	for _, name := range slices.Sorted(maps.Keys(zeroAllocArgs)) {
+		arg := zeroAllocArgs[name]
+		b.Run(name, func(b *testing.B) {
+			buf := make([]byte, 0, 256)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				buf = AppendArg(buf[:0], arg)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"git.fractalqb.de/fractalqb/sllm/v3/sllmcolor"
+)
+
+type color struct {
+	loc    locator
+	theme  sllmcolor.Theme
+	enable bool
+	buf    []byte
+	out    io.Writer
+}
+
+func runColor(args []string) error {
+	fs := flag.NewFlagSet("color", flag.ExitOnError)
+	cl := color{theme: sllmcolor.Default}
+	var force bool
+	cl.loc.flags(fs)
+	fs.StringVar(&cl.theme.Name, "name", cl.theme.Name, "SGR `parameters` for parameter names")
+	fs.StringVar(&cl.theme.Value, "value", cl.theme.Value, "SGR `parameters` for arguments")
+	fs.StringVar(&cl.theme.Error, "error", cl.theme.Error, "SGR `parameters` for argument errors")
+	fs.Func("param", "SGR parameters for arguments of a parameter as `name=sgr`", func(s string) error {
+		n, sgr, ok := strings.Cut(s, "=")
+		if !ok {
+			return errors.New("expect name=sgr")
+		}
+		if cl.theme.Values == nil {
+			cl.theme.Values = make(map[string]string)
+		}
+		cl.theme.Values[n] = sgr
+		return nil
+	})
+	fs.BoolVar(&force, "force", false, "colorize even if output is not a terminal")
+	fs.Parse(args)
+	if cl.loc.field != "" {
+		return errors.New("color cannot be used with -field")
+	}
+	cl.enable = force || sllmcolor.IsTerminal(os.Stdout)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	cl.out = out
+	return forLines(fs.Args(), cl.line)
+}
+
+// line writes the line with the colorized message. When coloring is enabled,
+// all text outside the message and lines with parse errors are escaped with
+// sllmcolor.Escape. Otherwise, lines are written unchanged.
+func (cl *color) line(_ string, _ int, line []byte) error {
+	if cl.enable {
+		cl.buf = cl.colorize(cl.buf[:0], line)
+		line = cl.buf
+	}
+	cl.out.Write(line)
+	_, err := cl.out.Write([]byte{'\n'})
+	return err
+}
+
+func (cl *color) colorize(to, line []byte) []byte {
+	if start, end, ok := cl.loc.span(line); ok {
+		res, err := cl.theme.Append(sllmcolor.Escape(to, string(line[:start])), string(line[start:end]))
+		if err == nil {
+			return sllmcolor.Escape(res, string(line[end:]))
+		}
+		to = res[:len(to)]
+	}
+	return sllmcolor.Escape(to, string(line))
+}
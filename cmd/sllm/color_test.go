@@ -0,0 +1,30 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/sllm/v3/sllmcolor"
+)
+
+func TestColor(t *testing.T) {
+	var out bytes.Buffer
+	cl := color{
+		theme:  sllmcolor.Theme{Value: "1"},
+		enable: true,
+		out:    &out,
+	}
+	const input = "`a:1` ok\nbroken `a\n" +
+		"\x1b]0;x\x07 broken `a\n" +
+		"\x1b[2J `a:\x1b[5m` \x07\n"
+	if err := scanLines("test", strings.NewReader(input), cl.line); err != nil {
+		t.Fatal(err)
+	}
+	const expect = "`a:\x1b[1m1\x1b[0m` ok\nbroken `a\n" +
+		"\\x1b]0;x\\x07 broken `a\n" +
+		"\\x1b[2J `a:\x1b[1m\\x1b[5m\x1b[0m` \\x07\n"
+	if s := out.String(); s != expect {
+		t.Errorf("unexpected output %q", s)
+	}
+}
@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+const grepDoc = `Expression syntax:
+  name=value   an argument of parameter name has the value
+  name!=value  no argument of parameter name has the value
+  name>num     also <, >=, <=: an argument of name compares as number
+  name~regexp  an argument of name matches the regular expression
+  has:name     the message has the parameter name
+  err:name     the parameter name has an argument error, err:* for any
+  not x, x and y, x or y, ( x )
+Predicates with spaces must be quoted, e.g. '"user=John Doe" and count>5'.`
+
+type grepArg struct {
+	name, value string
+	isErr       bool
+}
+
+type predicate interface {
+	match(args []grepArg) bool
+}
+
+type grep struct {
+	loc    locator
+	pred   predicate
+	invert bool
+	count  bool
+	lnos   bool
+	args   []grepArg
+	out    io.Writer
+	errOut io.Writer
+	n      int
+}
+
+func runGrep(args []string) error {
+	fs := flag.NewFlagSet("grep", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: sllm grep [flags] expression [file...]")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output(), grepDoc)
+	}
+	var g grep
+	g.loc.flags(fs)
+	fs.BoolVar(&g.invert, "v", false, "select non-matching lines")
+	fs.BoolVar(&g.count, "c", false, "only print the number of selected lines")
+	fs.BoolVar(&g.lnos, "n", false, "prefix output with the line number")
+	fs.Parse(args)
+	if fs.NArg() < 1 {
+		fs.Usage()
+		os.Exit(2)
+	}
+	var err error
+	if g.pred, err = parseGrepExpr(fs.Arg(0)); err != nil {
+		return err
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	g.out, g.errOut = out, os.Stderr
+	if err = forLines(fs.Args()[1:], g.line); err != nil {
+		return err
+	}
+	if g.count {
+		fmt.Fprintln(out, g.n)
+	}
+	return nil
+}
+
+func (g *grep) line(file string, lno int, line []byte) error {
+	msg, ok := g.loc.locate(line)
+	if !ok {
+		return nil
+	}
+	g.args = g.args[:0]
+	err := sllm.Parse(msg, nil, func(name, value string, isErr bool) error {
+		g.args = append(g.args, grepArg{name, sllm.UnescString(value), isErr})
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(g.errOut, "%s:%d: %s\n", file, lno, err)
+		return nil
+	}
+	if g.pred.match(g.args) == g.invert {
+		return nil
+	}
+	g.n++
+	if g.count {
+		return nil
+	}
+	if g.lnos {
+		fmt.Fprintf(g.out, "%d:", lno)
+	}
+	g.out.Write(line)
+	_, err = g.out.Write([]byte{'\n'})
+	return err
+}
+
+type (
+	notPred struct{ p predicate }
+	andPred struct{ l, r predicate }
+	orPred  struct{ l, r predicate }
+	hasPred struct{ name string }
+	errPred struct{ name string }
+	eqPred  struct{ name, value string }
+	rePred  struct {
+		name string
+		re   *regexp.Regexp
+	}
+	numPred struct {
+		name string
+		op   string
+		num  float64
+	}
+)
+
+func (p notPred) match(args []grepArg) bool { return !p.p.match(args) }
+func (p andPred) match(args []grepArg) bool { return p.l.match(args) && p.r.match(args) }
+func (p orPred) match(args []grepArg) bool  { return p.l.match(args) || p.r.match(args) }
+
+func (p hasPred) match(args []grepArg) bool {
+	for _, a := range args {
+		if a.name == p.name {
+			return true
+		}
+	}
+	return false
+}
+
+func (p errPred) match(args []grepArg) bool {
+	for _, a := range args {
+		if a.isErr && (p.name == "*" || a.name == p.name) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p eqPred) match(args []grepArg) bool {
+	for _, a := range args {
+		if !a.isErr && a.name == p.name && a.value == p.value {
+			return true
+		}
+	}
+	return false
+}
+
+func (p rePred) match(args []grepArg) bool {
+	for _, a := range args {
+		if !a.isErr && a.name == p.name && p.re.MatchString(a.value) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p numPred) match(args []grepArg) bool {
+	for _, a := range args {
+		if a.isErr || a.name != p.name {
+			continue
+		}
+		v, err := strconv.ParseFloat(a.value, 64)
+		if err != nil {
+			continue
+		}
+		switch p.op {
+		case "<":
+			if v < p.num {
+				return true
+			}
+		case "<=":
+			if v <= p.num {
+				return true
+			}
+		case ">":
+			if v > p.num {
+				return true
+			}
+		case ">=":
+			if v >= p.num {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func parseGrepExpr(expr string) (predicate, error) {
+	toks, err := grepTokens(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := grepParser{toks: toks}
+	res, err := p.or()
+	if err == nil && len(p.toks) > 0 {
+		err = fmt.Errorf("unexpected '%s'", p.toks[0])
+	}
+	return res, err
+}
+
+// grepTokens splits expr at white space outside of double quotes and removes
+// the quotes. Parentheses are separate tokens.
+func grepTokens(expr string) (toks []string, err error) {
+	var tok strings.Builder
+	inTok := false
+	flush := func() {
+		if inTok {
+			toks = append(toks, tok.String())
+			tok.Reset()
+			inTok = false
+		}
+	}
+	for expr != "" {
+		switch c := expr[0]; {
+		case c == '"':
+			q, err := strconv.QuotedPrefix(expr)
+			if err != nil {
+				return nil, fmt.Errorf("quoted string at '%s': %w", expr, err)
+			}
+			s, _ := strconv.Unquote(q)
+			tok.WriteString(s)
+			inTok = true
+			expr = expr[len(q):]
+			continue
+		case c == '(' || c == ')':
+			flush()
+			toks = append(toks, expr[:1])
+		case unicode.IsSpace(rune(c)):
+			flush()
+		default:
+			tok.WriteByte(c)
+			inTok = true
+		}
+		expr = expr[1:]
+	}
+	flush()
+	return toks, nil
+}
+
+type grepParser struct{ toks []string }
+
+func (p *grepParser) next(tok string) bool {
+	if len(p.toks) > 0 && p.toks[0] == tok {
+		p.toks = p.toks[1:]
+		return true
+	}
+	return false
+}
+
+func (p *grepParser) or() (predicate, error) {
+	l, err := p.and()
+	for err == nil && p.next("or") {
+		var r predicate
+		if r, err = p.and(); err == nil {
+			l = orPred{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *grepParser) and() (predicate, error) {
+	l, err := p.not()
+	for err == nil && p.next("and") {
+		var r predicate
+		if r, err = p.not(); err == nil {
+			l = andPred{l, r}
+		}
+	}
+	return l, err
+}
+
+func (p *grepParser) not() (predicate, error) {
+	switch {
+	case p.next("not"):
+		x, err := p.not()
+		return notPred{x}, err
+	case p.next("("):
+		x, err := p.or()
+		if err == nil && !p.next(")") {
+			err = errors.New("missing ')'")
+		}
+		return x, err
+	case len(p.toks) == 0:
+		return nil, errors.New("unexpected end of expression")
+	}
+	tok := p.toks[0]
+	p.toks = p.toks[1:]
+	return grepPredicate(tok)
+}
+
+func grepPredicate(tok string) (predicate, error) {
+	if n, ok := strings.CutPrefix(tok, "has:"); ok {
+		return hasPred{n}, nil
+	}
+	if n, ok := strings.CutPrefix(tok, "err:"); ok {
+		return errPred{n}, nil
+	}
+	i := strings.IndexAny(tok, "=!<>~")
+	if i <= 0 {
+		return nil, fmt.Errorf("invalid predicate '%s'", tok)
+	}
+	name, op := tok[:i], tok[i:i+1]
+	if i+1 < len(tok) && tok[i+1] == '=' && op != "=" && op != "~" {
+		op += "="
+	}
+	val := tok[i+len(op):]
+	switch op {
+	case "=":
+		return eqPred{name, val}, nil
+	case "!=":
+		return notPred{eqPred{name, val}}, nil
+	case "~":
+		re, err := regexp.Compile(val)
+		if err != nil {
+			return nil, err
+		}
+		return rePred{name, re}, nil
+	case "<", "<=", ">", ">=":
+		num, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return nil, fmt.Errorf("predicate '%s': %w", tok, err)
+		}
+		return numPred{name, op, num}, nil
+	}
+	return nil, fmt.Errorf("invalid operator in predicate '%s'", tok)
+}
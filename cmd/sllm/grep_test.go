@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+const grepInput = "added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`\n" +
+	"added `count:2` ⨉ `item:Shoe` to shopping cart by `user:Jane`\n" +
+	"John Doe logged in with `tx:4711`\n" +
+	"`user:John` failed: `err!(missing argument)`\n" +
+	"broken `line\n"
+
+func TestGrep(t *testing.T) {
+	test := func(t *testing.T, expr string, invert bool, expect ...int) {
+		t.Helper()
+		pred, err := parseGrepExpr(expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out, errOut bytes.Buffer
+		g := grep{pred: pred, invert: invert, lnos: true, out: &out, errOut: &errOut}
+		if err := scanLines("test", strings.NewReader(grepInput), g.line); err != nil {
+			t.Fatal(err)
+		}
+		var lnos []byte
+		for _, l := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+			if l != "" {
+				lnos = append(lnos, l[0])
+			}
+		}
+		var exp []byte
+		for _, n := range expect {
+			exp = append(exp, byte('0'+n))
+		}
+		if string(lnos) != string(exp) {
+			t.Errorf("'%s' selected lines %s, expected %s", expr, lnos, exp)
+		}
+		if s := errOut.String(); s != "test:5: unterminated arg name 'line'\n" {
+			t.Errorf("unexpected error output '%s'", s)
+		}
+	}
+	test(t, `"user=John Doe"`, false, 1)
+	test(t, `user="John Doe"`, false, 1)
+	test(t, `count>5`, false, 1)
+	test(t, `count<=2`, false, 2)
+	test(t, `item~^Ha`, false, 1)
+	test(t, `has:tx`, false, 3)
+	test(t, `err:*`, false, 4)
+	test(t, `err:user`, false)
+	test(t, `has:user and not user~^John`, false, 2)
+	test(t, `(item=Shoe or has:tx) and not count=2`, false, 3)
+	test(t, `user!=Jane and has:user`, false, 1, 4)
+	test(t, `has:user`, true, 3)
+}
+
+func TestGrep_exprErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"user",
+		"count>x",
+		"item~(",
+		"(has:a",
+		"has:a or",
+		"has:a has:b",
+		`user="John`,
+		"user!x",
+	} {
+		if _, err := parseGrepExpr(expr); err == nil {
+			t.Errorf("no error for '%s'", expr)
+		}
+	}
+}
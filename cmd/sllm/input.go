@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"regexp"
+)
+
+// locator finds the sllm message in a log line.
+type locator struct {
+	field string
+	re    *regexp.Regexp
+	reIdx int
+}
+
+func (loc *locator) flags(fs *flag.FlagSet) {
+	fs.StringVar(&loc.field, "field", "",
+		"lines are JSON objects, the message is the string `member`")
+	fs.Func("re",
+		"the message is the submatch 'msg' or the first submatch of `regexp`",
+		func(s string) (err error) {
+			if loc.re, err = regexp.Compile(s); err != nil {
+				return err
+			}
+			if loc.reIdx = loc.re.SubexpIndex("msg"); loc.reIdx < 0 {
+				if loc.re.NumSubexp() < 1 {
+					return errors.New("regexp has no submatch")
+				}
+				loc.reIdx = 1
+			}
+			return nil
+		},
+	)
+}
+
+// locate returns the message in line. If no message is found ok is false.
+func (loc *locator) locate(line []byte) (msg string, ok bool) {
+	switch {
+	case loc.field != "":
+		var obj map[string]json.RawMessage
+		if json.Unmarshal(line, &obj) != nil {
+			return "", false
+		}
+		raw, ok := obj[loc.field]
+		if !ok || json.Unmarshal(raw, &msg) != nil {
+			return "", false
+		}
+		return msg, true
+	}
+	start, end, ok := loc.span(line)
+	if !ok {
+		return "", false
+	}
+	return string(line[start:end]), true
+}
+
+// span returns the position of the message in line. It cannot be used with
+// JSON fields.
+func (loc *locator) span(line []byte) (start, end int, ok bool) {
+	if loc.re != nil {
+		m := loc.re.FindSubmatchIndex(line)
+		if m == nil || m[2*loc.reIdx] < 0 {
+			return 0, 0, false
+		}
+		return m[2*loc.reIdx], m[2*loc.reIdx+1], true
+	}
+	return 0, len(line), true
+}
+
+// forLines calls do for each line of the files or of stdin if files is empty.
+func forLines(files []string, do func(file string, lno int, line []byte) error) error {
+	if len(files) == 0 {
+		return scanLines("-", os.Stdin, do)
+	}
+	for _, f := range files {
+		r, err := os.Open(f)
+		if err != nil {
+			return err
+		}
+		err = scanLines(f, r, do)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func scanLines(file string, r io.Reader, do func(file string, lno int, line []byte) error) error {
+	scn := bufio.NewScanner(r)
+	scn.Buffer(nil, 1<<20)
+	for lno := 1; scn.Scan(); lno++ {
+		if err := do(file, lno, scn.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scn.Err()
+}
@@ -0,0 +1,66 @@
+// Command sllm processes log files with sllm messages. Usage:
+//
+//	sllm <command> [flags] [file...]
+//
+// Without files, input is read from stdin. Use "sllm <command> -h" for the
+// flags of a command. Commands are:
+//
+//	color   highlight parameters and arguments of sllm messages
+//	grep    select lines by the arguments of sllm messages
+//	redact  redact arguments of sllm messages
+//	stats   aggregate sllm messages by template
+//	tojson  convert sllm messages to JSON Lines
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+type command struct {
+	doc string
+	run func(args []string) error
+}
+
+var commands = map[string]command{
+	"color":  {"highlight parameters and arguments of sllm messages", runColor},
+	"grep":   {"select lines by the arguments of sllm messages", runGrep},
+	"redact": {"redact arguments of sllm messages", runRedact},
+	"stats":  {"aggregate sllm messages by template", runStats},
+	"tojson": {"convert sllm messages to JSON Lines", runToJSON},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		if a := os.Args[1]; a == "-h" || a == "-help" || a == "help" {
+			usage(os.Stdout)
+			return
+		}
+		fmt.Fprintf(os.Stderr, "sllm: unknown command '%s'\n", os.Args[1])
+		usage(os.Stderr)
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "sllm %s: %s\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: sllm <command> [flags] [file...]\ncommands:")
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(w, "  %-8s %s\n", n, commands[n].doc)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+type redact struct {
+	loc     locator
+	policy  sllm.RedactPolicy
+	keepBad bool
+	buf     []byte
+	out     io.Writer
+	errOut  io.Writer
+}
+
+func runRedact(args []string) error {
+	fs := flag.NewFlagSet("redact", flag.ExitOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: sllm redact [flags] [file...]")
+		fs.PrintDefaults()
+		fmt.Fprintln(fs.Output(), "Parameter patterns are matched with path.Match, first match wins.")
+	}
+	var (
+		rd      redact
+		mask    string
+		keyFile string
+		hashLen int
+		hashes  []string
+	)
+	rd.loc.flags(fs)
+	fs.StringVar(&mask, "mask-text", "***", "replacement text for masked values")
+	fs.Func("mask", "mask values of parameters matching `pattern`", func(p string) error {
+		rd.policy.Glob(p, func(to []byte, _ string) []byte { return sllm.EscString(to, mask) })
+		return nil
+	})
+	fs.Func("drop", "drop values of parameters matching `pattern`", func(p string) error {
+		rd.policy.Glob(p, sllm.Drop)
+		return nil
+	})
+	fs.Func("hash", "replace values of parameters matching `pattern` with HMAC", func(p string) error {
+		hashes = append(hashes, p)
+		return nil
+	})
+	fs.StringVar(&keyFile, "key", "", "read HMAC key from `file`, default is $SLLM_REDACT_KEY")
+	fs.IntVar(&hashLen, "hash-len", 8, "number of HMAC bytes to keep")
+	fs.BoolVar(&rd.keepBad, "keep-bad", false,
+		"write lines without message or with unparsable messages unchanged instead of dropping them")
+	fs.Parse(args)
+	if rd.loc.field != "" {
+		return errors.New("redact cannot be used with -field")
+	}
+	if len(hashes) > 0 {
+		key := []byte(os.Getenv("SLLM_REDACT_KEY"))
+		if keyFile != "" {
+			var err error
+			if key, err = os.ReadFile(keyFile); err != nil {
+				return err
+			}
+		}
+		if len(key) == 0 {
+			return errors.New("no HMAC key")
+		}
+		for _, p := range hashes {
+			rd.policy.Glob(p, sllm.HMAC(key, hashLen))
+		}
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	rd.out, rd.errOut = out, os.Stderr
+	return forLines(fs.Args(), rd.line)
+}
+
+// line writes the redacted line. Lines where no message is found or the
+// message cannot be parsed are reported to errOut and dropped unless keepBad
+// is set. Dropping is the default so that nothing leaks unredacted.
+func (rd *redact) line(file string, lno int, line []byte) error {
+	start, end, ok := rd.loc.span(line)
+	if !ok {
+		return rd.bad(file, lno, line, errors.New("no message"))
+	}
+	var err error
+	rd.buf = append(rd.buf[:0], line[:start]...)
+	rd.buf, err = sllm.RedactMessage(rd.buf, string(line[start:end]), &rd.policy)
+	if err != nil {
+		return rd.bad(file, lno, line, err)
+	}
+	rd.buf = append(rd.buf, line[end:]...)
+	return rd.write(rd.buf)
+}
+
+func (rd *redact) bad(file string, lno int, line []byte, err error) error {
+	fmt.Fprintf(rd.errOut, "%s:%d: %s\n", file, lno, err)
+	if rd.keepBad {
+		return rd.write(line)
+	}
+	return nil
+}
+
+func (rd *redact) write(line []byte) error {
+	rd.out.Write(line)
+	_, err := rd.out.Write([]byte{'\n'})
+	return err
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+func TestRedact(t *testing.T) {
+	var rd redact
+	fs := newTestFlags(&rd.loc)
+	if err := fs.Parse([]string{"-re", `^\[\w+\] (.*) \(end\)$`}); err != nil {
+		t.Fatal(err)
+	}
+	rd.policy.Glob("user*", sllm.Mask("***")).Name("email", sllm.Drop)
+	var out, errOut bytes.Buffer
+	rd.out, rd.errOut = &out, &errOut
+	const input = "[user] login by `user:John` `email:j@x.org` `n:1` (end)\n" +
+		"unmatched `user:John`\n" +
+		"[user] broken `user (end)\n"
+	if err := scanLines("test", strings.NewReader(input), rd.line); err != nil {
+		t.Fatal(err)
+	}
+	const expect = "[user] login by `user:***` `email:` `n:1` (end)\n"
+	if s := out.String(); s != expect {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+	const expectErr = "test:2: no message\n" +
+		"test:3: unterminated arg name 'user'\n"
+	if s := errOut.String(); s != expectErr {
+		t.Errorf("unexpected error output:\n%s", s)
+	}
+
+	out.Reset()
+	errOut.Reset()
+	rd.keepBad = true
+	if err := scanLines("test", strings.NewReader(input), rd.line); err != nil {
+		t.Fatal(err)
+	}
+	if s := out.String(); s != expect+"unmatched `user:John`\n[user] broken `user (end)\n" {
+		t.Errorf("unexpected output with -keep-bad:\n%s", s)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"git.fractalqb.de/fractalqb/sllm/v3/sllmstats"
+)
+
+func runStats(args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ExitOnError)
+	var (
+		loc   locator
+		stats sllmstats.Stats
+		top   int
+	)
+	loc.flags(fs)
+	fs.IntVar(&top, "top", 3, "number of most frequent values shown per parameter")
+	fs.IntVar(&stats.MaxValues, "max-values", sllmstats.DefaultMaxValues,
+		"maximum number of distinct values tracked per parameter")
+	fs.Parse(args)
+	err := forLines(fs.Args(), func(file string, lno int, line []byte) error {
+		if msg, ok := loc.locate(line); ok {
+			if err := stats.Add(sllmstats.Pos{File: file, Line: lno}, msg); err != nil {
+				fmt.Fprintf(os.Stderr, "%s:%d: %s\n", file, lno, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	writeStats(out, &stats, top)
+	return nil
+}
+
+func writeStats(w io.Writer, stats *sllmstats.Stats, top int) {
+	for _, t := range stats.Templates() {
+		fmt.Fprintf(w, "%d\tlines %s-%s\t%s\n", t.Count, t.First, t.Last, t.Template)
+		for _, p := range t.Params {
+			if p.Capped {
+				fmt.Fprintf(w, "\t%s: more than %d distinct", p.Name, p.Cardinality())
+			} else {
+				fmt.Fprintf(w, "\t%s: %d distinct", p.Name, p.Cardinality())
+			}
+			if p.ArgErrs > 0 {
+				fmt.Fprintf(w, ", %d errors", p.ArgErrs)
+			}
+			if p.IsNumeric() {
+				fmt.Fprintf(w, ", min %g, max %g, mean %g", p.Min, p.Max, p.Mean())
+			}
+			if top > 0 {
+				fmt.Fprint(w, ", top:")
+				for _, vc := range p.Top(top) {
+					fmt.Fprintf(w, " %q×%d", vc.Value, vc.Count)
+				}
+			}
+			fmt.Fprintln(w)
+		}
+	}
+}
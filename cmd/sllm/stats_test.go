@@ -0,0 +1,22 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/sllm/v3/sllmstats"
+)
+
+func TestWriteStats(t *testing.T) {
+	var stats sllmstats.Stats
+	stats.Add(sllmstats.Pos{File: "a.log", Line: 1}, "`n:1` of `who:a`")
+	stats.Add(sllmstats.Pos{File: "b.log", Line: 2}, "`n:3` of `who!(missing)`")
+	var sb strings.Builder
+	writeStats(&sb, &stats, 1)
+	const expect = "2\tlines a.log:1-b.log:2\t`n` of `who`\n" +
+		"\tn: 2 distinct, min 1, max 3, mean 2, top: \"1\"×1\n" +
+		"\twho: 1 distinct, 1 errors, top: \"a\"×1\n"
+	if s := sb.String(); s != expect {
+		t.Errorf("unexpected output:\n%s", s)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+type jsonMsg struct {
+	File    string         `json:"file,omitempty"`
+	Line    int            `json:"line"`
+	Tmpl    string         `json:"tmpl"`
+	Args    map[string]any `json:"args,omitempty"`
+	ArgErrs map[string]any `json:"argErrs,omitempty"`
+}
+
+type toJSON struct {
+	loc      locator
+	withFile bool
+	tmpl     bytes.Buffer
+	enc      *json.Encoder
+	errOut   io.Writer
+}
+
+func runToJSON(args []string) error {
+	fs := flag.NewFlagSet("tojson", flag.ExitOnError)
+	var tj toJSON
+	tj.loc.flags(fs)
+	fs.BoolVar(&tj.withFile, "file", false, "add the input file name to each object")
+	fs.Parse(args)
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	tj.enc = json.NewEncoder(out)
+	tj.enc.SetEscapeHTML(false)
+	tj.errOut = os.Stderr
+	return forLines(fs.Args(), tj.line)
+}
+
+// line writes the JSON object for a single log line. Lines without message are
+// skipped, parse errors are reported to errOut.
+func (tj *toJSON) line(file string, lno int, line []byte) error {
+	msg, ok := tj.loc.locate(line)
+	if !ok {
+		return nil
+	}
+	jm := jsonMsg{Line: lno}
+	if tj.withFile {
+		jm.File = file
+	}
+	tj.tmpl.Reset()
+	err := sllm.Parse(msg, &tj.tmpl, func(name, value string, argErr bool) error {
+		value = sllm.UnescString(value)
+		if argErr {
+			if jm.ArgErrs == nil {
+				jm.ArgErrs = make(map[string]any)
+			}
+			addValue(jm.ArgErrs, name, value)
+		} else {
+			if jm.Args == nil {
+				jm.Args = make(map[string]any)
+			}
+			addValue(jm.Args, name, value)
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Fprintf(tj.errOut, "%s:%d: %s\n", file, lno, err)
+		return nil
+	}
+	jm.Tmpl = tj.tmpl.String()
+	return tj.enc.Encode(jm)
+}
+
+// addValue sets m[name] to value. Repeated names collect their values in a
+// slice.
+func addValue(m map[string]any, name, value string) {
+	switch v := m[name].(type) {
+	case nil:
+		m[name] = value
+	case string:
+		m[name] = []string{v, value}
+	case []string:
+		m[name] = append(v, value)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestToJSON(t *testing.T) {
+	test := func(t *testing.T, loc locator, input, expect, expectErr string) {
+		t.Helper()
+		var out, errOut bytes.Buffer
+		tj := toJSON{loc: loc, enc: json.NewEncoder(&out), errOut: &errOut}
+		tj.enc.SetEscapeHTML(false)
+		if err := scanLines("test", strings.NewReader(input), tj.line); err != nil {
+			t.Fatal(err)
+		}
+		if s := out.String(); s != expect {
+			t.Errorf("unexpected output:\n%s", s)
+		}
+		if s := errOut.String(); s != expectErr {
+			t.Errorf("unexpected error output:\n%s", s)
+		}
+	}
+	t.Run("whole line", func(t *testing.T) {
+		test(t, locator{},
+			"added `count:7` ⨉ `item:Hat` by `user:John` and `user:Jane`\n"+
+				"broken `msg\n"+
+				"`x:a``b` failed: `err!(missing argument)`\n"+
+				"`err!(a)` and `err!(b)`\n",
+			`{"line":1,"tmpl":"added `+"`count` ⨉ `item` by `user` and `user`"+`","args":{"count":"7","item":"Hat","user":["John","Jane"]}}
+{"line":3,"tmpl":"`+"`x` failed: `err`"+`","args":{"x":"a`+"`"+`b"},"argErrs":{"err":"missing argument"}}
+{"line":4,"tmpl":"`+"`err` and `err`"+`","argErrs":{"err":["a","b"]}}
+`,
+			"test:2: unterminated arg name 'msg'\n",
+		)
+	})
+	t.Run("regexp", func(t *testing.T) {
+		var loc locator
+		fs := newTestFlags(&loc)
+		if err := fs.Parse([]string{"-re", `^\S+ \S+ (?P<msg>.*)$`}); err != nil {
+			t.Fatal(err)
+		}
+		test(t, loc, "2023-11-27 INFO hello `who:World`\nnomatch\n",
+			`{"line":1,"tmpl":"hello `+"`who`"+`","args":{"who":"World"}}
+`, "")
+	})
+	t.Run("json field", func(t *testing.T) {
+		test(t, locator{field: "msg"},
+			`{"level":"INFO","msg":"hello `+"`who:World`"+`"}`+"\n{\"level\":\"INFO\"}\n",
+			`{"line":1,"tmpl":"hello `+"`who`"+`","args":{"who":"World"}}
+`, "")
+	})
+}
+
+func newTestFlags(loc *locator) *flag.FlagSet {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	loc.flags(fs)
+	return fs
+}
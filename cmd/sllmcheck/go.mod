@@ -0,0 +1,19 @@
+module git.fractalqb.de/fractalqb/sllm/v3/cmd/sllmcheck
+
+go 1.23.0
+
+require (
+	git.fractalqb.de/fractalqb/sllm/v3/sllmcheck v0.0.0
+	golang.org/x/tools v0.33.0
+)
+
+require (
+	git.fractalqb.de/fractalqb/sllm/v3 v3.0.0 // indirect
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+)
+
+replace (
+	git.fractalqb.de/fractalqb/sllm/v3 => ../../
+	git.fractalqb.de/fractalqb/sllm/v3/sllmcheck => ../../sllmcheck
+)
@@ -0,0 +1,12 @@
+// Command sllmcheck checks sllm templates against their positional
+// arguments. Use it standalone or with go vet:
+//
+//	go vet -vettool=$(which sllmcheck) ./...
+package main
+
+import (
+	"git.fractalqb.de/fractalqb/sllm/v3/sllmcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() { singlechecker.Main(sllmcheck.Analyzer) }
@@ -0,0 +1,195 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/token"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+	"golang.org/x/tools/imports"
+)
+
+// message is a template that gets its own generated function.
+type message struct {
+	Func string
+	Tmpl string
+}
+
+type generator struct {
+	pkg   string
+	types map[string]string // parameter name → Go type
+	msgs  []message
+}
+
+// segment is the literal output that precedes the argument idx.
+type segment struct {
+	lit string
+	idx int
+}
+
+type funcParam struct {
+	idx   int
+	ident string
+	gtype string
+}
+
+func (g *generator) setType(name, gtype string) {
+	if g.types == nil {
+		g.types = make(map[string]string)
+	}
+	g.types[name] = gtype
+}
+
+func (g *generator) generate(src string) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by sllmgen from %s. DO NOT EDIT.\n\n", src)
+	fmt.Fprintf(&buf, "package %s\n\n", g.pkg)
+	fmt.Fprintln(&buf, `import (
+	"strconv"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)`)
+	for _, m := range g.msgs {
+		if err := g.genFunc(&buf, m); err != nil {
+			return nil, fmt.Errorf("%s: %w", m.Func, err)
+		}
+	}
+	return imports.Process("", buf.Bytes(), nil)
+}
+
+func (g *generator) genFunc(w *bytes.Buffer, m message) error {
+	var (
+		segs   []segment
+		params []funcParam
+		last   int
+	)
+	t, err := sllm.Compile(m.Tmpl)
+	if err != nil {
+		return err
+	}
+	for _, p := range t.ParamInfos(nil) {
+		if p.Format != "" {
+			return fmt.Errorf("format spec '%s' of parameter '%s' is not supported", p.Format, p.Name)
+		}
+	}
+	out, err := sllm.Append(nil, m.Tmpl, func(buf []byte, i int, n string) ([]byte, error) {
+		segs = append(segs, segment{lit: string(buf[last:]), idx: i})
+		last = len(buf)
+		if !slices.ContainsFunc(params, func(p funcParam) bool { return p.idx == i }) {
+			gtype := g.types[n]
+			if gtype == "" {
+				gtype = "string"
+			}
+			params = append(params, funcParam{idx: i, ident: goIdent(n), gtype: gtype})
+		}
+		return buf, nil
+	})
+	if err != nil {
+		return err
+	}
+	tail := string(out[last:])
+	slices.SortFunc(params, func(p, q funcParam) int { return p.idx - q.idx })
+	seen := make(map[string]int)
+	for i := range params {
+		p := &params[i]
+		if n := seen[p.ident]; n > 0 {
+			seen[p.ident] = n + 1
+			p.ident += strconv.Itoa(n + 1)
+		} else {
+			seen[p.ident] = 1
+		}
+	}
+
+	fmt.Fprintf(w, "\n// %s appends the sllm message:\n//\n//\t%s\nfunc %s(buf []byte", m.Func, docTemplate(m.Tmpl), m.Func)
+	for _, p := range params {
+		fmt.Fprintf(w, ", %s %s", p.ident, p.gtype)
+	}
+	fmt.Fprintln(w, ") []byte {")
+	for _, s := range segs {
+		fmt.Fprintf(w, "\tbuf = append(buf, %s...)\n", strconv.Quote(s.lit))
+		i := slices.IndexFunc(params, func(p funcParam) bool { return p.idx == s.idx })
+		fmt.Fprintf(w, "\tbuf = %s\n", appendCode(params[i].ident, params[i].gtype))
+	}
+	if tail != "" {
+		fmt.Fprintf(w, "\tbuf = append(buf, %s...)\n", strconv.Quote(tail))
+	}
+	fmt.Fprintln(w, "\treturn buf\n}")
+	return nil
+}
+
+func appendCode(v, gtype string) string {
+	switch gtype {
+	case "string":
+		return fmt.Sprintf("sllm.EscString(buf, %s)", v)
+	case "[]byte":
+		return fmt.Sprintf("sllm.EscBytes(buf, %s)", v)
+	case "bool":
+		return fmt.Sprintf("strconv.AppendBool(buf, %s)", v)
+	case "int64":
+		return fmt.Sprintf("strconv.AppendInt(buf, %s, 10)", v)
+	case "int", "int8", "int16", "int32":
+		return fmt.Sprintf("strconv.AppendInt(buf, int64(%s), 10)", v)
+	case "uint64":
+		return fmt.Sprintf("strconv.AppendUint(buf, %s, 10)", v)
+	case "uint", "uint8", "uint16", "uint32", "uintptr":
+		return fmt.Sprintf("strconv.AppendUint(buf, uint64(%s), 10)", v)
+	case "float64":
+		return fmt.Sprintf("strconv.AppendFloat(buf, %s, 'f', -1, 64)", v)
+	case "float32":
+		return fmt.Sprintf("strconv.AppendFloat(buf, float64(%s), 'f', -1, 32)", v)
+	case "time.Duration":
+		return fmt.Sprintf("sllm.EscString(buf, %s.String())", v)
+	case "sllm.Appender":
+		return fmt.Sprintf("%s.AppendSllm(buf)", v)
+	default:
+		return fmt.Sprintf("sllm.AppendArg(buf, %s)", v)
+	}
+}
+
+// docTemplate returns tmpl for use in a line comment. Templates with line
+// breaks or other non-printable characters are quoted.
+func docTemplate(tmpl string) string {
+	if strings.ContainsFunc(tmpl, func(r rune) bool { return !unicode.IsPrint(r) }) {
+		return strconv.Quote(tmpl)
+	}
+	return tmpl
+}
+
+// goIdent converts a parameter name into a Go identifier, e.g. "req.id"
+// becomes "reqID".
+func goIdent(name string) string {
+	var sb strings.Builder
+	upper := false
+	for _, r := range name {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+			if upper {
+				r = unicode.ToUpper(r)
+				upper = false
+			}
+			sb.WriteRune(r)
+		case unicode.IsDigit(r):
+			if sb.Len() == 0 {
+				sb.WriteByte('_')
+			}
+			sb.WriteRune(r)
+			upper = false
+		default:
+			upper = sb.Len() > 0
+		}
+	}
+	id := sb.String()
+	switch {
+	case id == "":
+		return "_p"
+	case token.IsKeyword(id), id == "buf":
+		return id + "_"
+	case strings.HasSuffix(id, "Id"):
+		return id[:len(id)-2] + "ID"
+	}
+	return id
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerator_catalog(t *testing.T) {
+	g := generator{pkg: "msgs"}
+	err := g.readCatalog(strings.NewReader(`# shop messages
+type count int
+
+AddedToCart = added ` + "`count` ⨉ `item` by `user` (`count:0`)"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, err := g.generate("msgs.sllm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expect = `func AddedToCart(buf []byte, count int, item string, user string) []byte {
+	buf = append(buf, "added ` + "`count:" + `"...)
+	buf = strconv.AppendInt(buf, int64(count), 10)
+	buf = append(buf, "` + "` ⨉ `item:" + `"...)
+	buf = sllm.EscString(buf, item)
+	buf = append(buf, "` + "` by `user:" + `"...)
+	buf = sllm.EscString(buf, user)
+	buf = append(buf, "` + "` (`count:" + `"...)
+	buf = strconv.AppendInt(buf, int64(count), 10)
+	buf = append(buf, "` + "`)" + `"...)
+	return buf
+}
+`
+	if !strings.Contains(string(code), expect) {
+		t.Errorf("unexpected code:\n%s", code)
+	}
+}
+
+func TestGenerator_readGo(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "msgs.go")
+	err := os.WriteFile(src, []byte(`package msgs
+
+//sllm:type n int
+
+const (
+	tmplCount = "counted `+"`n`"+` items"
+	tmplLines = "a\n`+"`b`"+`"
+	other     = "`+"`x`"+`"
+	tmplNum   = 4711
+)
+`), 0666)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var g generator
+	if err = g.readGo(src, "tmpl"); err != nil {
+		t.Fatal(err)
+	}
+	if g.pkg != "msgs" || g.types["n"] != "int" {
+		t.Errorf("unexpected package '%s' or types %v", g.pkg, g.types)
+	}
+	if len(g.msgs) != 2 || g.msgs[0] != (message{"Count", "counted `n` items"}) || g.msgs[1].Func != "Lines" {
+		t.Fatalf("unexpected messages %v", g.msgs)
+	}
+	code, err := g.generate("msgs.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, expect := range []string{
+		"func Count(buf []byte, n int) []byte {",
+		"//\t\"a\\n`b`\"\nfunc Lines(buf []byte, b string) []byte {",
+	} {
+		if !strings.Contains(string(code), expect) {
+			t.Errorf("missing '%s' in code:\n%s", expect, code)
+		}
+	}
+}
+
+func TestGenerator_badTemplate(t *testing.T) {
+	g := generator{pkg: "msgs", msgs: []message{{Func: "Bad", Tmpl: "`unterminated"}}}
+	if _, err := g.generate("test"); err == nil {
+		t.Error("template error not detected")
+	}
+}
+
+func TestGenerator_formatSpec(t *testing.T) {
+	g := generator{pkg: "msgs", msgs: []message{{Func: "Fmt", Tmpl: "took `d:%.2f`"}}}
+	_, err := g.generate("test")
+	if err == nil || err.Error() != "Fmt: format spec '%.2f' of parameter 'd' is not supported" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func Test_goIdent(t *testing.T) {
+	for name, expect := range map[string]string{
+		"user":        "user",
+		"req.id":      "reqID",
+		"http-status": "httpStatus",
+		"type":        "type_",
+		"buf":         "buf_",
+		"2nd":         "_2nd",
+		"...":         "_p",
+	} {
+		if id := goIdent(name); id != expect {
+			t.Errorf("'%s' → '%s', expected '%s'", name, id, expect)
+		}
+	}
+}
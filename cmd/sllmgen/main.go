@@ -0,0 +1,177 @@
+// Command sllmgen generates typed Go functions from sllm templates. The
+// generated functions append the message to a byte slice without boxing the
+// arguments into interfaces:
+//
+//	func AddedToCart(buf []byte, count int, item string, user string) []byte
+//
+// The templates are read either from a Go file or from a catalog file. In a Go
+// file each string constant whose name starts with the prefix (default "tmpl")
+// becomes a function named by the rest of the constant name. A catalog file
+// has lines of the form
+//
+//	AddedToCart = added `count` ⨉ `item` to shopping cart by `user`
+//
+// Empty lines and lines starting with '#' are ignored. Parameters are strings
+// unless their type is set with a line "type count int" in a catalog, a
+// comment "//sllm:type count int" in a Go file or the flag -type count=int.
+// Each distinct argument index of a template becomes one function parameter.
+//
+// Use it with go generate, e.g.:
+//
+//	//go:generate sllmgen messages.go
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("sllmgen: ")
+	var (
+		g         generator
+		out       string
+		prefix    string
+		flagTypes [][2]string
+	)
+	flag.StringVar(&out, "o", "", "output file, default is <input>_sllm.go")
+	flag.StringVar(&g.pkg, "pkg", os.Getenv("GOPACKAGE"), "package of the generated file")
+	flag.StringVar(&prefix, "prefix", "tmpl", "name prefix of template constants in Go files")
+	flag.Func("type", "set Go type of a parameter with `name=type`", func(s string) error {
+		name, gtype, ok := strings.Cut(s, "=")
+		if !ok {
+			return errors.New("expect name=type")
+		}
+		flagTypes = append(flagTypes, [2]string{name, gtype})
+		return nil
+	})
+	flag.Parse()
+	if flag.NArg() != 1 {
+		log.Fatal("expect exactly one input file")
+	}
+	in := flag.Arg(0)
+	var err error
+	if filepath.Ext(in) == ".go" {
+		err = g.readGo(in, prefix)
+	} else {
+		var r *os.File
+		if r, err = os.Open(in); err == nil {
+			err = g.readCatalog(r)
+			r.Close()
+		}
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, t := range flagTypes {
+		g.setType(t[0], t[1])
+	}
+	if g.pkg == "" {
+		log.Fatal("no package name, use -pkg")
+	}
+	code, err := g.generate(filepath.Base(in))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if out == "" {
+		out = strings.TrimSuffix(in, filepath.Ext(in)) + "_sllm.go"
+	}
+	if err = os.WriteFile(out, code, 0666); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func (g *generator) readGo(file, prefix string) error {
+	if prefix == "" {
+		return errors.New("empty constant prefix")
+	}
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, file, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+	if g.pkg == "" {
+		g.pkg = f.Name.Name
+	}
+	for _, cg := range f.Comments {
+		for _, c := range cg.List {
+			if def, ok := strings.CutPrefix(c.Text, "//sllm:type "); ok {
+				if err := g.typeDirective(def); err != nil {
+					return fmt.Errorf("%s: %w", fset.Position(c.Pos()), err)
+				}
+			}
+		}
+	}
+	for _, decl := range f.Decls {
+		gd, ok := decl.(*ast.GenDecl)
+		if !ok || gd.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gd.Specs {
+			vs := spec.(*ast.ValueSpec)
+			for i, n := range vs.Names {
+				fn, ok := strings.CutPrefix(n.Name, prefix)
+				if !ok || fn == "" || i >= len(vs.Values) {
+					continue
+				}
+				lit, ok := vs.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					continue
+				}
+				tmpl, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					return fmt.Errorf("%s: %w", fset.Position(lit.Pos()), err)
+				}
+				g.msgs = append(g.msgs, message{Func: fn, Tmpl: tmpl})
+			}
+		}
+	}
+	return nil
+}
+
+func (g *generator) readCatalog(r io.Reader) error {
+	scn := bufio.NewScanner(r)
+	for lno := 1; scn.Scan(); lno++ {
+		line := strings.TrimSpace(scn.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		if def, ok := strings.CutPrefix(line, "type "); ok {
+			if err := g.typeDirective(def); err != nil {
+				return fmt.Errorf("line %d: %w", lno, err)
+			}
+			continue
+		}
+		fn, tmpl, ok := strings.Cut(line, "=")
+		if !ok {
+			return fmt.Errorf("line %d: expect 'Name = template'", lno)
+		}
+		fn = strings.TrimSpace(fn)
+		if !token.IsIdentifier(fn) {
+			return fmt.Errorf("line %d: invalid function name '%s'", lno, fn)
+		}
+		g.msgs = append(g.msgs, message{Func: fn, Tmpl: strings.TrimSpace(tmpl)})
+	}
+	return scn.Err()
+}
+
+func (g *generator) typeDirective(def string) error {
+	f := strings.Fields(def)
+	if len(f) != 2 {
+		return fmt.Errorf("invalid type definition '%s'", def)
+	}
+	g.setType(f[0], f[1])
+	return nil
+}
@@ -0,0 +1,97 @@
+package sllm
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// CauseParam is the name of the template parameter whose argument is wrapped
+// by an [Err] if it is an error, similar to the %w verb of [fmt.Errorf].
+const CauseParam = "cause"
+
+// Err is an error that keeps the template and the arguments of its message.
+// The message is rendered with positional arguments, like [StringIdx], only
+// when calling Error. Arguments of the parameter [CauseParam] that are errors
+// are wrapped, i.e. they are returned by Unwrap. Errors of the template are
+// wrapped too.
+type Err struct {
+	tmpl string
+	args []any
+	once sync.Once
+	msg  string
+	err  error
+}
+
+// NewErr creates an Err from the template tmpl and the positional arguments
+// args. The arguments are kept as they are, not copied. The message is
+// rendered on the first call to Error, i.e. it reflects changes made to
+// referenced data, e.g. a reused []byte or a mutated struct pointer, until
+// then. Such arguments must not be modified concurrently with Error. Use
+// [ErrorIdx] to render the message immediately.
+func NewErr(tmpl string, args ...any) *Err {
+	return &Err{tmpl: tmpl, args: args}
+}
+
+func (e *Err) Error() string {
+	e.render()
+	return e.msg
+}
+
+func (e *Err) render() {
+	e.once.Do(func() {
+		s, err := StringIdx(e.tmpl, e.args...)
+		if err != nil && !errors.Is(err, ArgErrors{}) {
+			e.err = fmt.Errorf("appending '%s': %w", s, err)
+			e.msg = e.err.Error()
+		} else {
+			e.msg = s
+		}
+	})
+}
+
+// Template returns the message template of e.
+func (e *Err) Template() string { return e.tmpl }
+
+// Args returns the positional arguments of e.
+func (e *Err) Args() []any { return e.args }
+
+// Arg returns the argument for the parameter with the given name.
+func (e *Err) Arg(name string) (any, bool) {
+	for i, n := range Params(e.tmpl) {
+		if n == name && i >= 0 && i < len(e.args) {
+			return e.args[i], true
+		}
+	}
+	return nil, false
+}
+
+// Unwrap returns the arguments of parameter [CauseParam] that are errors and
+// the error of the template, if any.
+func (e *Err) Unwrap() (errs []error) {
+	if e.render(); e.err != nil {
+		errs = append(errs, e.err)
+	}
+	for i, n := range Params(e.tmpl) {
+		if n != CauseParam || i < 0 || i >= len(e.args) {
+			continue
+		}
+		if err, ok := e.args[i].(error); ok {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// LogValue makes e a [slog.LogValuer]. It resolves to a group with the
+// template as "tmpl" and an attribute for each parameter of the template.
+func (e *Err) LogValue() slog.Value {
+	attrs := []slog.Attr{slog.String("tmpl", e.tmpl)}
+	for i, n := range Params(e.tmpl) {
+		if i >= 0 && i < len(e.args) {
+			attrs = append(attrs, slog.Any(n, e.args[i]))
+		}
+	}
+	return slog.GroupValue(attrs...)
+}
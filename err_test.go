@@ -0,0 +1,79 @@
+package sllm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"testing"
+)
+
+func ExampleErr() {
+	err := fmt.Errorf("checkout: %w",
+		ErrorIdx("cannot add `item` for `user`: `cause`", "Hat", "John Doe", io.ErrUnexpectedEOF),
+	)
+	fmt.Println(err)
+	var e *Err
+	if errors.As(err, &e) {
+		user, _ := e.Arg("user")
+		fmt.Println(user)
+	}
+	fmt.Println(errors.Is(err, io.ErrUnexpectedEOF))
+	// Output:
+	// checkout: cannot add `item:Hat` for `user:John Doe`: `cause:unexpected EOF`
+	// John Doe
+	// true
+}
+
+func TestErr(t *testing.T) {
+	t.Run("missing arg", func(t *testing.T) {
+		err := NewErr("`a` and `b`", 1)
+		if s := err.Error(); s != "`a:1` and `b!(missing argument 1 'b')`" {
+			t.Errorf("unexpected message '%s'", s)
+		}
+		if _, ok := err.Arg("b"); ok {
+			t.Error("found missing arg")
+		}
+	})
+	t.Run("bad template", func(t *testing.T) {
+		err := NewErr("`a` and `b", 1)
+		if s := err.Error(); s != "appending '`a:1` and `': unterminated parameter" {
+			t.Errorf("unexpected message '%s'", s)
+		}
+		errs := err.Unwrap()
+		if len(errs) != 1 || errors.Unwrap(errs[0]).Error() != "unterminated parameter" {
+			t.Errorf("template error not wrapped: %v", errs)
+		}
+	})
+	t.Run("snapshot", func(t *testing.T) {
+		buf := []byte("a")
+		err := ErrorIdx("`v`", buf)
+		buf[0] = 'b'
+		if s := err.Error(); s != "`v:a`" {
+			t.Errorf("unexpected message '%s'", s)
+		}
+	})
+	t.Run("no cause", func(t *testing.T) {
+		err := NewErr("`err`", io.EOF)
+		if errors.Is(err, io.EOF) {
+			t.Error("wraps non-cause parameter")
+		}
+	})
+	t.Run("log value", func(t *testing.T) {
+		var buf bytes.Buffer
+		log := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{
+			ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.TimeKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}))
+		log.Info("failed", "err", NewErr("`item` for `count:0`", "Hat", 7))
+		const expect = "level=INFO msg=failed err.tmpl=\"`item` for `count:0`\" err.item=Hat err.count=Hat\n"
+		if s := buf.String(); s != expect {
+			t.Errorf("unexpected log '%s'", s)
+		}
+	})
+}
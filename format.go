@@ -0,0 +1,233 @@
+package sllm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// argFormat is the format spec of a template parameter `name:%spec`. The spec
+// is applied to typed values from a [ValuesFunc] and to the argument text an
+// ArgsFunc appended otherwise.
+type argFormat struct {
+	verb byte
+	spec string     // fmt format with a single verb, not used for 'T'
+	time TimeFormat // time format for verb 'T'
+}
+
+var timeFlags = map[string]TimeFormat{
+	"TDefault":   TDefault,
+	"TUTC":       TUTC,
+	"TNoDate":    TNoDate,
+	"TNoWeekday": TNoWeekday,
+	"TYear":      TYear,
+	"TNoClock":   TNoClock,
+	"TMillis":    TMillis,
+	"TMicros":    TMicros,
+}
+
+// splitParam splits the parameter n of a template into the name, the explicit
+// index and the format spec. Both, index and format spec are optional:
+// `name`, `name:idx`, `name:%spec` or `name:idx%spec`.
+func splitParam(n string) (name string, idx int, hasIdx bool, f argFormat, err error) {
+	colon := strings.IndexByte(n, nameSepChar)
+	if colon < 0 {
+		return n, 0, false, f, nil
+	}
+	if colon == 0 {
+		return "", 0, false, f, fmt.Errorf("empty parameter in '%s'", n)
+	}
+	name, rest := n[:colon], n[colon+1:]
+	if pct := strings.IndexByte(rest, '%'); pct >= 0 {
+		if f, err = parseFormat(rest[pct:]); err != nil {
+			return name, 0, false, f, fmt.Errorf("format in '%s': %w", n, err)
+		}
+		if rest = rest[:pct]; rest == "" {
+			return name, 0, false, f, nil
+		}
+	}
+	if idx, err = strconv.Atoi(rest); err != nil {
+		return name, 0, false, f, fmt.Errorf("index in '%s': %w", n, err)
+	}
+	return name, idx, true, f, nil
+}
+
+func parseFormat(s string) (f argFormat, err error) {
+	if flags, ok := strings.CutPrefix(s, "%T"); ok {
+		f.verb, f.spec = 'T', s
+		if flags == "" {
+			return f, nil
+		}
+		if len(flags) < 2 || flags[0] != '{' || flags[len(flags)-1] != '}' {
+			return f, fmt.Errorf("invalid time flags '%s'", flags)
+		}
+		for _, fl := range strings.Split(flags[1:len(flags)-1], "|") {
+			tf, ok := timeFlags[fl]
+			if !ok {
+				return f, fmt.Errorf("unknown time flag '%s'", fl)
+			}
+			f.time |= tf
+		}
+		return f, nil
+	}
+	if len(s) < 2 {
+		return f, errors.New("missing verb")
+	}
+	f.verb, f.spec = s[len(s)-1], s
+	if strings.IndexByte("vsqtdboxXcUeEfFgG", f.verb) < 0 {
+		return f, fmt.Errorf("unsupported verb '%c'", f.verb)
+	}
+	for i := 1; i < len(s)-1; i++ {
+		if strings.IndexByte("+-# 0123456789.", s[i]) < 0 {
+			return f, fmt.Errorf("invalid character '%c' in '%s'", s[i], s)
+		}
+	}
+	return f, nil
+}
+
+// apply reformats the argument buf[start:] according to f. The argument text
+// is interpreted as required by the verb, e.g. as number for 'f' or 'd'. If
+// the ArgsFunc truncated the buffer, e.g. to only collect the parameters,
+// there is nothing to format.
+func (f *argFormat) apply(buf []byte, start int) ([]byte, error) {
+	if f.verb == 0 || start > len(buf) {
+		return buf, nil
+	}
+	val := UnescString(string(buf[start:]))
+	buf = buf[:start]
+	var (
+		v   any
+		err error
+	)
+	switch f.verb {
+	case 'T':
+		var u Unmarshaler
+		t, err := u.parseTime(val)
+		if err != nil {
+			return buf, fmt.Errorf("format '%s': %w", f.spec, err)
+		}
+		return f.time.Append(buf, t), nil
+	case 't':
+		v, err = strconv.ParseBool(val)
+	case 'd', 'b', 'o', 'c', 'U':
+		v, err = parseInt(val)
+	case 'e', 'E', 'f', 'F', 'g', 'G':
+		v, err = strconv.ParseFloat(val, 64)
+	case 'x', 'X':
+		if v, err = parseInt(val); err != nil {
+			if v, err = strconv.ParseFloat(val, 64); err != nil {
+				v, err = val, nil
+			}
+		}
+	default:
+		v = val
+	}
+	if err != nil {
+		return buf, fmt.Errorf("format '%s': %w", f.spec, err)
+	}
+	return escTail(fmt.Appendf(buf, f.spec, v), start), nil
+}
+
+// appendArg appends the argument i with name n either from args, then the
+// text is reformatted, or from vals, then the typed value is formatted.
+func (f *argFormat) appendArg(to []byte, i int, n string, args ArgsFunc, vals ValuesFunc) ([]byte, error) {
+	if vals != nil {
+		v, err := vals(i, n)
+		if err != nil {
+			return to, err
+		}
+		return f.appendValue(to, v)
+	}
+	start := len(to)
+	to, err := args(to, i, n)
+	if err != nil {
+		return to, err
+	}
+	return f.apply(to, start)
+}
+
+// appendValue appends v formatted according to f. Values of the types that
+// fmt formats natively for the verb are formatted directly. Other values are
+// appended with AppendArg and their text is reformatted with apply.
+func (f *argFormat) appendValue(to []byte, v any) ([]byte, error) {
+	start := len(to)
+	if f.verb == 'T' {
+		switch t := v.(type) {
+		case time.Time:
+			return f.time.Append(to, t), nil
+		case *time.Time:
+			if t != nil {
+				return f.time.Append(to, *t), nil
+			}
+		}
+	} else if f.verb != 0 && f.native(v) {
+		if strings.IndexByte("eEfFgG", f.verb) >= 0 {
+			if x, ok := intFloat(v); ok {
+				v = x
+			}
+		}
+		return escTail(fmt.Appendf(to, f.spec, v), start), nil
+	}
+	return f.apply(AppendArg(to, v), start)
+}
+
+// native reports whether v has a type that fmt formats with f's verb.
+func (f *argFormat) native(v any) bool {
+	switch v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return strings.IndexByte("vdboxXcUeEfFgG", f.verb) >= 0
+	case float32, float64:
+		return strings.IndexByte("vxXeEfFgG", f.verb) >= 0
+	case string, []byte:
+		return strings.IndexByte("vsqxX", f.verb) >= 0
+	case bool:
+		return f.verb == 'v' || f.verb == 't'
+	}
+	return false
+}
+
+// intFloat converts integers to float64 for the float verbs.
+func intFloat(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	}
+	return 0, false
+}
+
+// escTail escapes backticks in buf[start:].
+func escTail(buf []byte, start int) []byte {
+	if bytes.IndexByte(buf[start:], tmplEscChar) >= 0 {
+		val := string(buf[start:])
+		buf = EscString(buf[:start], val)
+	}
+	return buf
+}
+
+func parseInt(s string) (any, error) {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i, nil
+	}
+	return strconv.ParseUint(s, 10, 64)
+}
@@ -0,0 +1,144 @@
+package sllm
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func ExampleAppend_format() {
+	at := time.Date(2023, 11, 27, 21, 30, 0, 123456789, time.UTC)
+	buf, _ := Append(nil,
+		"`latency:%.2f`s `mask:%x` `at:%T{TUTC|TMillis}` `latency:0%08.3f`",
+		IdxArgs(0.12345, 255, at),
+	)
+	fmt.Println(string(buf))
+	// Output:
+	// `latency:0.12`s `mask:ff` `at:11-27 Mo 21:30:00.123` `latency:0000.123`
+}
+
+func TestAppend_format(t *testing.T) {
+	tests := []struct {
+		tmpl   string
+		arg    any
+		expect string
+	}{
+		{"`a:%5d`", 42, "`a:   42`"},
+		{"`a:%x`", "a`b", "`a:616062`"},
+		{"`a:%q`", "a`b", "`a:\"a``b\"`"},
+		{"`a:%t`", true, "`a:true`"},
+		{"`a:%e`", 1500, "`a:1.500000e+03`"},
+		{"`a:%x`", uint64(1 << 63), "`a:8000000000000000`"},
+		{"`a:%d`", "foo", "`a!(format '%d': strconv.ParseUint: parsing \"foo\": invalid syntax)`"},
+		{"`a:%T{TNoDate|TUTC}`", "2023-11-27T21:30:00+01:00", "`a:20:30:00`"},
+		{"`a:%T{TYear|TNoClock|TNoWeekday|TUTC}`", time.Date(2023, 11, 27, 21, 30, 0, 0, time.UTC), "`a:2023-11-27`"},
+	}
+	for _, test := range tests {
+		t.Run(test.tmpl, func(t *testing.T) {
+			buf, _ := Append(nil, test.tmpl, IdxArgs(test.arg))
+			if s := string(buf); s != test.expect {
+				t.Errorf("Append: unexpected %s", s)
+			}
+			buf, _ = MustCompile(test.tmpl).Append(nil, IdxArgs(test.arg))
+			if s := string(buf); s != test.expect {
+				t.Errorf("Template: unexpected %s", s)
+			}
+		})
+	}
+}
+
+func ExampleAppendValues() {
+	const tmpl = "`mask:%x` `mask:1%x`"
+	buf, _ := Append(nil, tmpl, IdxArgs(1234, "1234"))
+	fmt.Println(string(buf))
+	buf, _ = AppendValues(nil, tmpl, IdxValues(1234, "1234"))
+	fmt.Println(string(buf))
+	// Output:
+	// `mask:4d2` `mask:4d2`
+	// `mask:4d2` `mask:31323334`
+}
+
+func TestAppendValues_format(t *testing.T) {
+	tests := []struct {
+		tmpl   string
+		arg    any
+		expect string
+	}{
+		{"`a`", "x`y", "`a:x``y`"},
+		{"`a:%x`", 1234, "`a:4d2`"},
+		{"`a:%x`", "1234", "`a:31323334`"},
+		{"`a:%x`", "12a4", "`a:31326134`"},
+		{"`a:%x`", []byte("a`b"), "`a:616062`"},
+		{"`a:%q`", "a`b", "`a:\"a``b\"`"},
+		{"`a:%.1f`", 3, "`a:3.0`"},
+		{"`a:%5s`", 7, "`a:    7`"},
+		{"`a:%t`", true, "`a:true`"},
+		{"`a:%d`", "foo", "`a!(format '%d': strconv.ParseUint: parsing \"foo\": invalid syntax)`"},
+		{"`a:%T{TNoDate|TUTC}`", time.Date(2023, 11, 27, 21, 30, 0, 0, time.UTC), "`a:21:30:00`"},
+		{"`a:%T{TNoDate|TUTC}`", "2023-11-27T21:30:00+01:00", "`a:20:30:00`"},
+	}
+	for _, test := range tests {
+		t.Run(test.tmpl, func(t *testing.T) {
+			buf, _ := AppendValues(nil, test.tmpl, IdxValues(test.arg))
+			if s := string(buf); s != test.expect {
+				t.Errorf("AppendValues: unexpected %s", s)
+			}
+			buf, _ = MustCompile(test.tmpl).AppendValues(nil, NmValues(map[string]any{"a": test.arg}))
+			if s := string(buf); s != test.expect {
+				t.Errorf("Template: unexpected %s", s)
+			}
+		})
+	}
+	if _, err := AppendValues(nil, "`a` `b`", IdxValues(1)); err == nil {
+		t.Error("missing argument not detected")
+	}
+}
+
+func TestAppend_formatErrors(t *testing.T) {
+	for tmpl, expect := range map[string]string{
+		"`a:%`":         "format in 'a:%': missing verb",
+		"`a:%y`":        "format in 'a:%y': unsupported verb 'y'",
+		"`a:%*d`":       "format in 'a:%*d': invalid character '*' in '%*d'",
+		"`a:%T{`":       "format in 'a:%T{': invalid time flags '{'",
+		"`a:%T{TUTC|}`": "format in 'a:%T{TUTC|}': unknown time flag ''",
+		"`a:x%d`":       "index in 'a:x%d': strconv.Atoi: parsing \"x\": invalid syntax",
+	} {
+		if _, err := Append(nil, tmpl, IdxArgs(1)); err == nil || err.Error() != expect {
+			t.Errorf("Append '%s': unexpected error %v", tmpl, err)
+		}
+		if _, err := Compile(tmpl); err == nil || err.Error() != expect {
+			t.Errorf("Compile '%s': unexpected error %v", tmpl, err)
+		}
+	}
+}
+
+func TestFormat_params(t *testing.T) {
+	const tmpl = "x `a:%d` y `b:%.2f` `cause`"
+	ps, err := Parameters(tmpl, nil)
+	if err != nil || fmt.Sprint(ps) != "[a b cause]" {
+		t.Errorf("Parameters: %v, %v", ps, err)
+	}
+	ps = ps[:0]
+	it := IterParams(tmpl)
+	for _, n := range it.All() {
+		ps = append(ps, n)
+	}
+	if it.Err() != nil || fmt.Sprint(ps) != "[a b cause]" {
+		t.Errorf("Params: %v, %v", ps, it.Err())
+	}
+	e := NewErr(tmpl, 7, 1.5, io.EOF)
+	if !errors.Is(e, io.EOF) {
+		t.Error("cause not wrapped")
+	}
+	if a, ok := e.Arg("b"); !ok || a != 1.5 {
+		t.Errorf("Arg: %v, %t", a, ok)
+	}
+	if v := e.LogValue(); len(v.Group()) != 4 {
+		t.Errorf("LogValue: %v", v)
+	}
+	if s := e.Error(); s != "x `a:7` y `b:1.50` `cause:EOF`" {
+		t.Errorf("Error: %s", s)
+	}
+}
@@ -1,3 +1,3 @@
 module git.fractalqb.de/fractalqb/sllm/v3
 
-go 1.21.4
+go 1.23.0
@@ -0,0 +1,108 @@
+package sllm
+
+import (
+	"errors"
+	"iter"
+)
+
+var errStopIter = errors.New("stop iteration")
+
+// Args returns an iterator over the parameter names and argument values of
+// the sllm message msg. Iteration stops silently at the first syntax error in
+// msg. Use [ArgIter] to get error flags, offsets and the parse error.
+func Args(msg string) iter.Seq2[string, string] {
+	return func(yield func(string, string) bool) {
+		parse(msg, nil, func(name, value string, _ int, _ bool) error {
+			if !yield(name, value) {
+				return errStopIter
+			}
+			return nil
+		})
+	}
+}
+
+// ArgInfo describes an argument found in a message.
+type ArgInfo struct {
+	// Value of the argument; for argument errors it is the error message.
+	Value string
+	// IsError is true for argument errors `name!(error)`.
+	IsError bool
+	// Offset is the byte offset of Value in the message.
+	Offset int
+}
+
+// ArgIter iterates over the arguments of a sllm message and keeps the
+// parse error, if any.
+type ArgIter struct {
+	msg string
+	err error
+}
+
+// IterArgs creates an ArgIter for the message msg.
+func IterArgs(msg string) *ArgIter { return &ArgIter{msg: msg} }
+
+// All returns an iterator over the parameter names and the argument info of
+// the message.
+func (it *ArgIter) All() iter.Seq2[string, ArgInfo] {
+	return func(yield func(string, ArgInfo) bool) {
+		it.err = parse(it.msg, nil, func(name, value string, off int, isErr bool) error {
+			if !yield(name, ArgInfo{Value: value, IsError: isErr, Offset: off}) {
+				return errStopIter
+			}
+			return nil
+		})
+		if errors.Is(it.err, errStopIter) {
+			it.err = nil
+		}
+	}
+}
+
+// Err returns the parse error from the last iteration.
+func (it *ArgIter) Err() error { return it.err }
+
+// Params returns an iterator over the argument indices and parameter names of
+// the template tmpl. Iteration stops silently at the first syntax error in
+// tmpl. Use [ParamIter] to get the error.
+func Params(tmpl string) iter.Seq2[int, string] {
+	it := ParamIter{tmpl: tmpl}
+	return it.All()
+}
+
+// ParamIter iterates over the parameters of a template and keeps the
+// template error, if any.
+type ParamIter struct {
+	tmpl string
+	err  error
+}
+
+// IterParams creates a ParamIter for the template tmpl.
+func IterParams(tmpl string) *ParamIter { return &ParamIter{tmpl: tmpl} }
+
+// All returns an iterator over the argument indices and parameter names of the
+// template.
+func (it *ParamIter) All() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		stop := false
+		_, it.err = Append(nil, it.tmpl, func(to []byte, idx int, n string) ([]byte, error) {
+			if !stop && !yield(idx, n) {
+				stop = true
+			}
+			return to[:0], nil
+		})
+	}
+}
+
+// Err returns the template error from the last iteration.
+func (it *ParamIter) Err() error { return it.err }
+
+// Params returns an iterator over the argument indices and parameter names of
+// t.
+func (t *Template) Params() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for _, p := range t.params {
+			if !yield(p.idx, p.name) {
+				return
+			}
+		}
+	}
+}
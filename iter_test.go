@@ -0,0 +1,80 @@
+package sllm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleArgs() {
+	msg := "added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`"
+	for name, val := range Args(msg) {
+		fmt.Println(name, val)
+	}
+	// Output:
+	// count 7
+	// item Hat
+	// user John Doe
+}
+
+func ExampleArgIter() {
+	msg := "`user:John` failed with `err!(missing argument)` and `broken"
+	it := IterArgs(msg)
+	for name, arg := range it.All() {
+		fmt.Println(name, arg.Value, arg.IsError, msg[arg.Offset:arg.Offset+len(arg.Value)])
+	}
+	fmt.Println(it.Err())
+	// Output:
+	// user John false John
+	// err missing argument true missing argument
+	// unterminated arg name 'broken'
+}
+
+func ExampleParams() {
+	for idx, name := range Params("`a`, `b:11`, `c`, `d:0`") {
+		fmt.Println(idx, name)
+	}
+	// Output:
+	// 0 a
+	// 11 b
+	// 1 c
+	// 0 d
+}
+
+func TestArgIter_break(t *testing.T) {
+	it := IterArgs("`a:1` `b:2` `c`")
+	n := 0
+	for range it.All() {
+		n++
+		break
+	}
+	if n != 1 {
+		t.Errorf("iterated %d times", n)
+	}
+	if err := it.Err(); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+}
+
+func TestParamIter(t *testing.T) {
+	it := IterParams("`a` `b` `c` `d")
+	var names []string
+	for _, n := range it.All() {
+		names = append(names, n)
+		if n == "b" {
+			break
+		}
+	}
+	if s := fmt.Sprint(names); s != "[a b]" {
+		t.Errorf("unexpected names %s", s)
+	}
+	if err := it.Err(); err == nil {
+		t.Error("template error not detected")
+	}
+	names = names[:0]
+	for _, n := range MustCompile("`x` and `y:0`").Params() {
+		names = append(names, n)
+	}
+	if s := fmt.Sprint(names); s != "[x y]" {
+		t.Errorf("unexpected names %s", s)
+	}
+}
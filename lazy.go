@@ -0,0 +1,45 @@
+package sllm
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// Lazy is an argument that is evaluated only when the parameter is rendered.
+// [AppendArg] appends the result of calling it. Use it for arguments that are
+// expensive to compute. Note that a Lazy is called each time its parameter
+// occurs in a template.
+type Lazy func() any
+
+// LogValue makes f a [slog.LogValuer], i.e. f is also evaluated lazily by
+// log/slog.
+func (f Lazy) LogValue() slog.Value { return slog.AnyValue(f()) }
+
+// IdxArgsLazy is like IdxArgs for Lazy arguments. Arguments of parameters
+// that do not occur in the template are not evaluated.
+func IdxArgsLazy(args ...Lazy) ArgsFunc {
+	return func(buf []byte, i int, n string) ([]byte, error) {
+		if i < 0 || i >= len(args) {
+			return buf, fmt.Errorf("missing argument %d '%s'", i, n)
+		}
+		return appendLazy(buf, args[i], nil), nil
+	}
+}
+
+// NmArgsLazy is like NmArgs for Lazy arguments. Arguments of parameters that
+// do not occur in the template are not evaluated.
+func NmArgsLazy(args map[string]Lazy) ArgsFunc {
+	return func(buf []byte, i int, n string) ([]byte, error) {
+		if a, ok := args[n]; ok {
+			return appendLazy(buf, a, nil), nil
+		}
+		return buf, fmt.Errorf("missing argument %d '%s'", i, n)
+	}
+}
+
+func appendLazy(to []byte, f Lazy, r *Renderers) []byte {
+	if f == nil {
+		return append(to, nilArg...)
+	}
+	return appendArg(to, f(), r)
+}
@@ -0,0 +1,53 @@
+package sllm
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleLazy() {
+	calls := 0
+	body := Lazy(func() any {
+		calls++
+		return "expensive"
+	})
+	buf, _ := Append(nil, "`a`", IdxArgs(1, body))
+	fmt.Println(string(buf), calls)
+	buf, _ = Append(nil, "`a` `b`", IdxArgs(1, body))
+	fmt.Println(string(buf), calls)
+	// Output:
+	// `a:1` 0
+	// `a:1` `b:expensive` 1
+}
+
+func TestLazyArgs(t *testing.T) {
+	var calls []string
+	lazy := func(n string) Lazy {
+		return func() any {
+			calls = append(calls, n)
+			return n
+		}
+	}
+	buf, _ := Append(nil, "`a` `c:2`", IdxArgsLazy(lazy("a"), lazy("b"), lazy("c")))
+	if s := string(buf); s != "`a:a` `c:c`" {
+		t.Errorf("IdxArgsLazy: unexpected message '%s'", s)
+	}
+	if fmt.Sprint(calls) != "[a c]" {
+		t.Errorf("IdxArgsLazy: unexpected calls %v", calls)
+	}
+	calls = nil
+	buf, _ = Append(nil, "`b` `x`", NmArgsLazy(map[string]Lazy{"a": lazy("a"), "b": lazy("b"), "x": nil}))
+	if s := string(buf); s != "`b:b` `x:<nil>`" {
+		t.Errorf("NmArgsLazy: unexpected message '%s'", s)
+	}
+	if fmt.Sprint(calls) != "[b]" {
+		t.Errorf("NmArgsLazy: unexpected calls %v", calls)
+	}
+	if v := Lazy(func() any { return 7 }).LogValue(); v.Any() != int64(7) {
+		t.Errorf("LogValue: unexpected %v", v)
+	}
+	f := func() any { return 4711 }
+	if s := string(AppendArg(nil, f)); s != "4711" {
+		t.Errorf("func() any: unexpected '%s'", s)
+	}
+}
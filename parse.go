@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
-	"strings"
 )
 
 // Parameters extracs the parameter names from template tmpl and appends them
@@ -22,83 +21,127 @@ func Parameters(tmpl string, a []string) ([]string, error) {
 // passed as tmpl Parse will also reconstruct the original template into the
 // buffer. Note that the template is appended to tmpl's content.
 func Parse(msg string, tmpl *bytes.Buffer, onArg func(name, value string, argError bool) error) error {
-	for len(msg) > 0 {
-		idx := strings.IndexByte(msg, tmplEscChar)
+	return parse(msg, tmpl, func(name, value string, _ int, argError bool) error {
+		return onArg(name, value, argError)
+	})
+}
+
+// parse implements Parse and additionally passes the byte offset of value in
+// msg to onArg.
+func parse(msg string, tmpl *bytes.Buffer, onArg func(name, value string, off int, argError bool) error) error {
+	text := func(string) {}
+	if tmpl != nil {
+		text = func(s string) { tmpl.WriteString(s) }
+	}
+	return scan(msg, text, onArg)
+}
+
+// ParseBytes is the []byte variant of Parse that does not allocate. The
+// reconstructed template is appended to tmpl and returned. The name and value
+// passed to onArg are sub-slices of msg.
+func ParseBytes(msg []byte, tmpl []byte, onArg func(name, value []byte, argError bool) error) ([]byte, error) {
+	err := scan(msg,
+		func(s []byte) { tmpl = append(tmpl, s...) },
+		func(name, value []byte, _ int, argError bool) error {
+			return onArg(name, value, argError)
+		},
+	)
+	return tmpl, err
+}
+
+// scan is the parser shared by Parse and ParseBytes. It calls text with the
+// pieces of the reconstructed template and onArg with each argument and the
+// byte offset of its value in msg.
+func scan[S ~string | ~[]byte](
+	msg S,
+	text func(S),
+	onArg func(name, value S, off int, argError bool) error,
+) error {
+	p := 0
+	for p < len(msg) {
+		idx := indexByte(msg[p:], tmplEscChar)
 		if idx < 0 {
-			if tmpl != nil {
-				tmpl.WriteString(msg)
-			}
+			text(msg[p:])
 			return nil
 		}
-		if tmpl != nil {
-			tmpl.WriteString(msg[:idx])
-		}
-		msg = msg[idx+1:]
+		text(msg[p : p+idx])
+		p += idx + 1
 		switch {
-		case msg == "":
+		case p == len(msg):
 			return errors.New("empty arg")
-		case msg[0] == tmplEscChar:
-			msg = msg[1:]
+		case msg[p] == tmplEscChar:
+			text(msg[p-1 : p+1])
+			p++
 			continue
 		}
-		idx = strings.IndexAny(msg, nameEnd)
+		nmStart := p
+		idx = indexByte2(msg[p:], nameSepChar, argErrChar)
 		if idx < 0 {
-			return fmt.Errorf("unterminated arg name '%s'", msg)
+			return fmt.Errorf("unterminated arg name '%s'", msg[p:])
 		}
-		name := msg[:idx]
-		isErr := msg[idx] == '!'
+		name := msg[p : p+idx]
+		p += idx
+		isErr := msg[p] == argErrChar
 		if isErr {
 			switch {
-			case idx+1 >= len(msg):
+			case p+1 >= len(msg):
 				return fmt.Errorf("no error marker for arg '%s'", name)
-			case msg[idx+1] != '(':
-				return fmt.Errorf("invalid error start marker '%c'", msg[idx+1])
+			case msg[p+1] != '(':
+				return fmt.Errorf("invalid error start marker '%c'", msg[p+1])
 			}
-			msg = msg[idx+2:]
+			p += 2
 		} else {
-			msg = msg[idx+1:]
+			p++
 		}
-		idx = strings.IndexByte(msg, tmplEscChar)
+		end := p
 		for {
+			idx = indexByte(msg[end:], tmplEscChar)
 			if idx < 0 {
 				return fmt.Errorf("unterminated arg '%s'", name)
 			}
-			if idx+1 >= len(msg) || msg[idx+1] != tmplEscChar {
+			end += idx
+			if end+1 >= len(msg) || msg[end+1] != tmplEscChar {
 				break
 			}
-			nidx := strings.IndexByte(msg[idx+2:], tmplEscChar)
-			if nidx < 0 {
-				return nil
-			}
-			idx += nidx + 2
+			end += 2
 		}
-		var value string
+		value := msg[p:end]
 		if isErr {
-			if r := msg[idx-1]; r != ')' {
+			if r := msg[end-1]; r != ')' {
 				return fmt.Errorf("invalid error end marker '%c'", r)
 			}
-			value = msg[:idx-1]
-		} else {
-			value = msg[:idx]
+			value = msg[p : end-1]
 		}
-		err := onArg(name, value, isErr)
-		if err != nil {
+		if err := onArg(name, value, p, isErr); err != nil {
 			if isErr {
 				return fmt.Errorf("error arg '%s': %w", name, err)
 			}
 			return fmt.Errorf("arg '%s': %w", name, err)
 		}
-		if tmpl != nil {
-			tmpl.WriteRune('`')
-			tmpl.WriteString(name)
-			tmpl.WriteRune('`')
-		}
-		msg = msg[idx+1:]
+		text(msg[nmStart-1 : nmStart+len(name)])
+		text(msg[end : end+1])
+		p = end + 1
 	}
 	return nil
 }
 
-var nameEnd = string([]byte{nameSepChar, argErrChar})
+func indexByte[S ~string | ~[]byte](s S, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexByte2[S ~string | ~[]byte](s S, c, d byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c || s[i] == d {
+			return i
+		}
+	}
+	return -1
+}
 
 // ParseMap uses Parse to create a map with all parameters assigned to an
 // argument in the passed message msg. ParseMap can also reconstruct the
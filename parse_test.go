@@ -117,6 +117,16 @@ func TestParse(t *testing.T) {
 			t.Errorf("unexpected error: %s", err)
 		}
 	})
+	t.Run("unterminated arg with escape", func(t *testing.T) {
+		var tmpl bytes.Buffer
+		_, err := ParseMap("there is no `arg:4711``", &tmpl)
+		switch {
+		case err == nil:
+			t.Error("error not detected")
+		case err.Error() != "unterminated arg 'arg'":
+			t.Errorf("unexpected error: %s", err)
+		}
+	})
 }
 
 func Test_OutAndParse(t *testing.T) {
@@ -180,3 +190,76 @@ func Test_OutAndParse(t *testing.T) {
 		t.Run(n, func(t *testing.T) { test(t, c.tmpl, c.args) })
 	}
 }
+
+func TestParse_escapedTemplate(t *testing.T) {
+	const tmpl = "tic `` and `arg` with ``"
+	msg, _ := StringIdx(tmpl, "x`y")
+	var ptmpl bytes.Buffer
+	if err := Parse(msg, &ptmpl, func(_, _ string, _ bool) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if s := ptmpl.String(); s != tmpl {
+		t.Errorf("template [%s] changed to [%s]", tmpl, s)
+	}
+}
+
+func TestParseBytes(t *testing.T) {
+	for _, msg := range []string{
+		"",
+		"no args",
+		"added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`",
+		"tic `` and `arg:x``y` with ``",
+		"`a:1` failed with `err!(missing argument)`",
+		"there is no arg `",
+		"there is no `arg`",
+		"there is no `arg!(bla>`",
+		"there is no `arg:4711``",
+	} {
+		t.Run(msg, func(t *testing.T) {
+			var (
+				stmpl bytes.Buffer
+				sargs []string
+				bargs []string
+			)
+			serr := Parse(msg, &stmpl, func(n, v string, e bool) error {
+				sargs = append(sargs, fmt.Sprint(n, v, e))
+				return nil
+			})
+			btmpl, berr := ParseBytes([]byte(msg), nil, func(n, v []byte, e bool) error {
+				bargs = append(bargs, fmt.Sprint(string(n), string(v), e))
+				return nil
+			})
+			if fmt.Sprint(serr) != fmt.Sprint(berr) {
+				t.Errorf("error '%v' differs from '%v'", berr, serr)
+			}
+			if serr == nil && string(btmpl) != stmpl.String() {
+				t.Errorf("template '%s' differs from '%s'", btmpl, stmpl.String())
+			}
+			if !reflect.DeepEqual(sargs, bargs) {
+				t.Errorf("args %v differ from %v", bargs, sargs)
+			}
+		})
+	}
+}
+
+func TestParseBytes_allocs(t *testing.T) {
+	msg := []byte("added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`")
+	tmpl := make([]byte, 0, 256)
+	onArg := func(_, _ []byte, _ bool) error { return nil }
+	allocs := testing.AllocsPerRun(100, func() {
+		tmpl, _ = ParseBytes(msg, tmpl[:0], onArg)
+	})
+	if allocs != 0 {
+		t.Errorf("%f allocations per message", allocs)
+	}
+}
+
+func BenchmarkParseBytes(b *testing.B) {
+	msg := []byte("`service:rsyslog`: Sent `signal:SIGHUP` to main `process:1611` (`name:rsyslogd`)")
+	var tmpl []byte
+	onArg := func(_, v []byte, _ bool) error { outBytes += len(v); return nil }
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		tmpl, _ = ParseBytes(msg, tmpl[:0], onArg)
+	}
+}
@@ -0,0 +1,97 @@
+package sllm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Reader reads sllm messages line by line from an io.Reader. For each line it
+// provides the reconstructed template and the arguments. Internal buffers are
+// reused, i.e. all data returned from a Reader is only valid until the next
+// call to Scan.
+type Reader struct {
+	scn     *bufio.Scanner
+	line    int
+	tmpl    []byte
+	args    []Arg
+	lineErr error
+}
+
+// Arg is a single argument of a sllm message. Value is not unescaped. Name
+// and Value refer to the internal buffer of the Reader, i.e. they must be
+// copied to keep them beyond the next call to Reader.Scan.
+type Arg struct {
+	Name    []byte
+	Value   []byte
+	IsError bool
+}
+
+// LineError is an error that occurred when reading a specific line.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// NewReader creates a Reader that reads sllm messages from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{scn: bufio.NewScanner(r)}
+}
+
+// Buffer sets the initial buffer and the maximum line length, see
+// bufio.Scanner.Buffer.
+func (r *Reader) Buffer(buf []byte, max int) { r.scn.Buffer(buf, max) }
+
+// Scan reads the next line and parses it. It returns false when the end of
+// input is reached or an I/O error occurred, see Err. Scan returns true if the
+// line cannot be parsed, the parse error is returned by LineErr.
+func (r *Reader) Scan() bool {
+	r.tmpl = r.tmpl[:0]
+	r.args = r.args[:0]
+	r.lineErr = nil
+	if !r.scn.Scan() {
+		return false
+	}
+	r.line++
+	var err error
+	r.tmpl, err = ParseBytes(r.scn.Bytes(), r.tmpl,
+		func(name, value []byte, isErr bool) error {
+			r.args = append(r.args, Arg{Name: name, Value: value, IsError: isErr})
+			return nil
+		},
+	)
+	if err != nil {
+		r.lineErr = &LineError{Line: r.line, Err: err}
+	}
+	return true
+}
+
+// Err returns the first non-EOF I/O error of the Reader.
+func (r *Reader) Err() error {
+	if err := r.scn.Err(); err != nil {
+		return &LineError{Line: r.line + 1, Err: err}
+	}
+	return nil
+}
+
+// LineErr returns the parse error of the current line, if any. Non-nil errors
+// are of type *LineError.
+func (r *Reader) LineErr() error { return r.lineErr }
+
+// Line returns the 1-based number of the current line.
+func (r *Reader) Line() int { return r.line }
+
+// Bytes returns the current line.
+func (r *Reader) Bytes() []byte { return r.scn.Bytes() }
+
+// Template returns the reconstructed template of the current line.
+func (r *Reader) Template() []byte { return r.tmpl }
+
+// Args returns the arguments of the current line.
+func (r *Reader) Args() []Arg { return r.args }
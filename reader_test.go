@@ -0,0 +1,77 @@
+package sllm
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func ExampleReader() {
+	r := NewReader(strings.NewReader(
+		"added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`\n" +
+			"plain text\n" +
+			"`user:John` failed with `err!(missing argument)`\n",
+	))
+	for r.Scan() {
+		fmt.Printf("%d: %s\n", r.Line(), r.Template())
+		for _, a := range r.Args() {
+			fmt.Printf("  %s=%s %t\n", a.Name, a.Value, a.IsError)
+		}
+	}
+	// Output:
+	// 1: added `count` ⨉ `item` to shopping cart by `user`
+	//   count=7 false
+	//   item=Hat false
+	//   user=John Doe false
+	// 2: plain text
+	// 3: `user` failed with `err`
+	//   user=John false
+	//   err=missing argument true
+}
+
+func TestReader_lineErr(t *testing.T) {
+	r := NewReader(strings.NewReader("ok\nbroken `arg\nok `a:1`\n"))
+	var lines []string
+	for r.Scan() {
+		if err := r.LineErr(); err != nil {
+			var lerr *LineError
+			if !errors.As(err, &lerr) || lerr.Line != 2 {
+				t.Errorf("unexpected error: %s", err)
+			}
+			continue
+		}
+		lines = append(lines, string(r.Template()))
+	}
+	if err := r.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if s := strings.Join(lines, "|"); s != "ok|ok `a`" {
+		t.Errorf("unexpected templates '%s'", s)
+	}
+}
+
+func TestReader_allocs(t *testing.T) {
+	const line = "`service:rsyslog`: Sent `signal:SIGHUP` to main `process:1611`\n"
+	input := strings.NewReader(strings.Repeat(line, 100))
+	r := NewReader(input)
+	r.Scan()
+	allocs := testing.AllocsPerRun(50, func() { r.Scan() })
+	if allocs != 0 {
+		t.Errorf("%f allocations per line", allocs)
+	}
+}
+
+func BenchmarkReader(b *testing.B) {
+	const line = "`service:rsyslog`: Sent `signal:SIGHUP` to main `process:1611` (`name:rsyslogd`)\n"
+	input := strings.NewReader(strings.Repeat(line, 1000))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		input.Seek(0, 0)
+		r := NewReader(input)
+		for r.Scan() {
+			outBytes += len(r.Template())
+		}
+	}
+}
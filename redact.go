@@ -0,0 +1,165 @@
+package sllm
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"unicode/utf8"
+)
+
+// Redaction appends the redacted form of the unescaped argument value to the
+// buffer to. Implementations must escape their output, e.g. with [EscString].
+type Redaction func(to []byte, value string) []byte
+
+// Mask returns a Redaction that replaces every value with mask.
+func Mask(mask string) Redaction {
+	return func(to []byte, _ string) []byte { return EscString(to, mask) }
+}
+
+// HMAC returns a Redaction that replaces a value with the hex encoded
+// HMAC-SHA256 of the value using key. Equal values result in equal hashes,
+// which allows correlating pseudonymized values. The hash is truncated to n
+// bytes if 0 < n < 32.
+func HMAC(key []byte, n int) Redaction {
+	if n <= 0 || n > sha256.Size {
+		n = sha256.Size
+	}
+	return func(to []byte, value string) []byte {
+		h := hmac.New(sha256.New, key)
+		h.Write([]byte(value))
+		var sum [sha256.Size]byte
+		return hex.AppendEncode(to, h.Sum(sum[:0])[:n])
+	}
+}
+
+// Partial returns a Redaction that keeps the first head and the last tail
+// runes of a value and replaces all other runes with '*'. Values that are not
+// longer than head+tail runes are masked completely.
+func Partial(head, tail int) Redaction {
+	return func(to []byte, value string) []byte {
+		n, h, t := utf8.RuneCountInString(value), head, tail
+		if n <= h+t {
+			h, t = 0, 0
+		}
+		i := 0
+		for _, r := range value {
+			if i >= h && i < n-t {
+				r = '*'
+			}
+			if r == '`' {
+				to = append(to, '`')
+			}
+			to = utf8.AppendRune(to, r)
+			i++
+		}
+		return to
+	}
+}
+
+// Truncate returns a Redaction that keeps the first n runes of a value and
+// replaces the rest with '…'. Negative n is treated as 0.
+func Truncate(n int) Redaction {
+	n = max(n, 0)
+	return func(to []byte, value string) []byte {
+		i := 0
+		for p := range value {
+			if i == n {
+				return append(EscString(to, value[:p]), "…"...)
+			}
+			i++
+		}
+		return EscString(to, value)
+	}
+}
+
+// RedactPolicy selects the Redaction for a parameter name. Rules are checked
+// in the order they were added, the first matching rule wins. The zero value
+// is an empty policy that redacts nothing.
+type RedactPolicy struct {
+	rules []redactRule
+}
+
+type redactRule struct {
+	match  func(name string) bool
+	redact Redaction
+}
+
+// Name adds a rule for the parameter name.
+func (p *RedactPolicy) Name(name string, r Redaction) *RedactPolicy {
+	return p.Func(func(n string) bool { return n == name }, r)
+}
+
+// Glob adds a rule for all parameter names that match pattern with
+// [path.Match], e.g. "user.*". Invalid patterns do not match.
+func (p *RedactPolicy) Glob(pattern string, r Redaction) *RedactPolicy {
+	return p.Func(func(n string) bool {
+		ok, _ := path.Match(pattern, n)
+		return ok
+	}, r)
+}
+
+// Func adds a rule for all parameter names for which match returns true.
+func (p *RedactPolicy) Func(match func(name string) bool, r Redaction) *RedactPolicy {
+	p.rules = append(p.rules, redactRule{match: match, redact: r})
+	return p
+}
+
+// Redaction returns the Redaction for the parameter name or nil if the
+// parameter is not redacted.
+func (p *RedactPolicy) Redaction(name string) Redaction {
+	if p == nil {
+		return nil
+	}
+	for _, r := range p.rules {
+		if r.match(name) {
+			return r.redact
+		}
+	}
+	return nil
+}
+
+// Redact wraps args so that the arguments of parameters selected by policy
+// are redacted. The `name:value` markup of redacted parameters is kept.
+// Argument errors of args are passed through unchanged.
+func Redact(args ArgsFunc, policy *RedactPolicy) ArgsFunc {
+	return func(buf []byte, i int, n string) ([]byte, error) {
+		r := policy.Redaction(n)
+		if r == nil {
+			return args(buf, i, n)
+		}
+		start := len(buf)
+		buf, err := args(buf, i, n)
+		if err != nil {
+			return buf, err
+		}
+		val := UnescString(string(buf[start:]))
+		return r(buf[:start], val), nil
+	}
+}
+
+// Drop is a Redaction that removes the value but keeps the parameter markup.
+func Drop(to []byte, _ string) []byte { return to }
+
+// RedactMessage parses the sllm message msg, redacts the arguments selected by
+// policy and appends the result to the buffer to. The template text and all
+// other arguments are copied unchanged. The text of argument errors is
+// redacted too. If msg cannot be parsed, to is returned unchanged with the
+// error.
+func RedactMessage(to []byte, msg string, policy *RedactPolicy) ([]byte, error) {
+	start, last := len(to), 0
+	err := parse(msg, nil, func(name, value string, off int, _ bool) error {
+		r := policy.Redaction(name)
+		if r == nil {
+			return nil
+		}
+		to = append(to, msg[last:off]...)
+		to = r(to, UnescString(value))
+		last = off + len(value)
+		return nil
+	})
+	if err != nil {
+		return to[:start], err
+	}
+	return append(to, msg[last:]...), nil
+}
@@ -0,0 +1,98 @@
+package sllm
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func ExampleRedact() {
+	var policy RedactPolicy
+	policy.Name("user", Partial(1, 1)).
+		Glob("*mail", Mask("<redacted>")).
+		Name("card", Truncate(4))
+	Fprint(os.Stdout, "`user` with `email` paid with `card` for `item`\n",
+		Redact(IdxArgs("John Doe", "john@example.com", "4111111111111111", "Hat"), &policy),
+	)
+	// Output:
+	// `user:J******e` with `email:<redacted>` paid with `card:4111…` for `item:Hat`
+}
+
+func TestRedact(t *testing.T) {
+	key := []byte("secret")
+	var policy RedactPolicy
+	policy.Name("user", HMAC(key, 8)).
+		Func(func(n string) bool { return n == "tic" }, Partial(1, 0))
+	args := Redact(IdxArgs("John", "John", "a`b`c"), &policy)
+	out, err := Append(nil, "`user` `user` `tic` `missing`", args)
+	if err == nil {
+		t.Error("missing argument not reported")
+	}
+	h := string(HMAC(key, 8)(nil, "John"))
+	if len(h) != 16 {
+		t.Errorf("hash length %d", len(h))
+	}
+	expect := "`user:" + h + "` `user:" + h + "` `tic:a****` `missing!(missing argument 3 'missing')`"
+	if s := string(out); s != expect {
+		t.Errorf("unexpected output '%s'", s)
+	}
+	if s := string(Partial(0, 1)(nil, "x`")); s != "*``" {
+		t.Errorf("not escaped: '%s'", s)
+	}
+	if s := string(Truncate(1)(nil, "``")); s != "``…" {
+		t.Errorf("not escaped: '%s'", s)
+	}
+	if s := string(Truncate(-1)(nil, "4111111111111111")); s != "…" {
+		t.Errorf("negative length not clamped: '%s'", s)
+	}
+	if r := (*RedactPolicy)(nil).Redaction("user"); r != nil {
+		t.Error("nil policy redacts")
+	}
+}
+
+func TestRedact_format(t *testing.T) {
+	var policy RedactPolicy
+	policy.Name("d", Mask("***"))
+	out, _ := Append(nil, "`d:%5s` `d:0%.1f`", Redact(IdxArgs(1.25), &policy))
+	const expect = "`d:  ***` `d!(format '%.1f': strconv.ParseFloat: parsing \"***\": invalid syntax)`"
+	if s := string(out); s != expect {
+		t.Errorf("unexpected output '%s'", s)
+	}
+}
+
+func ExampleRedactMessage() {
+	var policy RedactPolicy
+	policy.Name("user", Mask("***")).Name("email", Drop).Name("err", Truncate(7))
+	msg, _ := RedactMessage(nil,
+		"`user:John Doe` with `email:john@example.com` failed: `err!(missing argument)`, `n:1`",
+		&policy,
+	)
+	fmt.Println(string(msg))
+	// Output:
+	// `user:***` with `email:` failed: `err!(missing…)`, `n:1`
+}
+
+func TestRedactMessage(t *testing.T) {
+	var policy RedactPolicy
+	policy.Name("a", Partial(1, 1))
+	for msg, expect := range map[string]string{
+		"":                          "",
+		"no args":                   "no args",
+		"tic `` `b:x``y` `a:x``yz`": "tic `` `b:x``y` `a:x**z`",
+		"`a:1```":                   "`a:**`",
+	} {
+		out, err := RedactMessage([]byte("> "), msg, &policy)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := string(out); s != "> "+expect {
+			t.Errorf("'%s' redacted to '%s'", msg, s)
+		}
+	}
+	for _, msg := range []string{"broken `a", "mail `a:john@x.org``"} {
+		out, err := RedactMessage([]byte("> "), msg, &policy)
+		if err == nil || string(out) != "> " {
+			t.Errorf("unexpected result '%s', %v", out, err)
+		}
+	}
+}
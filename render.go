@@ -0,0 +1,120 @@
+package sllm
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Renderers is a registry of functions that append values of types that
+// cannot implement [Appender], e.g. types from third-party packages.
+// Renderers are consulted by AppendArg after the built-in types and before
+// the error, [fmt.Stringer] and fmt fallbacks. Renderers for a type take
+// precedence over type switch functions, which are called in the order they
+// were added.
+//
+// Reading the registry is lock-free. Adding renderers is safe for concurrent
+// use but copies the registry, i.e. it is meant for the setup phase. The zero
+// value is an empty registry.
+type Renderers struct {
+	mu  sync.Mutex
+	tab atomic.Pointer[renderTable]
+}
+
+// DefaultRenderers is the global registry used by [AppendArg] and the
+// ArgsFuncs of the package.
+var DefaultRenderers Renderers
+
+type renderTable struct {
+	types    map[reflect.Type]func([]byte, any) []byte
+	switches []func([]byte, any) ([]byte, bool)
+}
+
+// Type registers the function render for values with dynamic type t. render
+// must escape its output, e.g. with [EscString].
+func (r *Renderers) Type(t reflect.Type, render func(to []byte, v any) []byte) *Renderers {
+	r.update(func(tab *renderTable) { tab.types[t] = render })
+	return r
+}
+
+// Switch registers the type switch function render. It returns false if it
+// cannot append v. render must escape its output, e.g. with [EscString].
+func (r *Renderers) Switch(render func(to []byte, v any) ([]byte, bool)) *Renderers {
+	r.update(func(tab *renderTable) { tab.switches = append(tab.switches, render) })
+	return r
+}
+
+// RenderType registers render for values of type T with r. If T is an
+// interface type, render is registered as type switch function for all values
+// that implement T because values never have an interface as dynamic type.
+func RenderType[T any](r *Renderers, render func(to []byte, v T) []byte) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Interface {
+		r.Switch(func(to []byte, v any) ([]byte, bool) {
+			if tv, ok := v.(T); ok {
+				return render(to, tv), true
+			}
+			return to, false
+		})
+		return
+	}
+	r.Type(t, func(to []byte, v any) []byte {
+		return render(to, v.(T))
+	})
+}
+
+func (r *Renderers) update(f func(*renderTable)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	tab := &renderTable{types: make(map[reflect.Type]func([]byte, any) []byte)}
+	if old := r.tab.Load(); old != nil {
+		for t, f := range old.types {
+			tab.types[t] = f
+		}
+		tab.switches = append(tab.switches, old.switches...)
+	}
+	f(tab)
+	r.tab.Store(tab)
+}
+
+// Append appends v with the renderer registered for it. If there is none, to
+// is returned unchanged with false.
+func (r *Renderers) Append(to []byte, v any) ([]byte, bool) {
+	tab := r.tab.Load()
+	if tab == nil {
+		return to, false
+	}
+	if f, ok := tab.types[reflect.TypeOf(v)]; ok {
+		return f(to, v), true
+	}
+	for _, f := range tab.switches {
+		if res, ok := f(to, v); ok {
+			return res, true
+		}
+	}
+	return to, false
+}
+
+// AppendArg works like the package level function AppendArg but consults r
+// before DefaultRenderers.
+func (r *Renderers) AppendArg(to []byte, v any) []byte { return appendArg(to, v, r) }
+
+// IdxArgs works like the package level function IdxArgs but appends the
+// arguments with r.AppendArg.
+func (r *Renderers) IdxArgs(args ...any) ArgsFunc { return idxArgs(r, args) }
+
+// IdxArgsDefault works like the package level function IdxArgsDefault but
+// appends the arguments with r.AppendArg.
+func (r *Renderers) IdxArgsDefault(d any, args ...any) ArgsFunc {
+	return idxArgsDefault(r, d, args)
+}
+
+// NmArgs works like the package level function NmArgs but appends the
+// arguments with r.AppendArg.
+func (r *Renderers) NmArgs(args map[string]any) ArgsFunc { return nmArgs(r, args) }
+
+// NmArgsDefault works like the package level function NmArgsDefault but
+// appends the arguments with r.AppendArg.
+func (r *Renderers) NmArgsDefault(d any, args map[string]any) ArgsFunc {
+	return nmArgsDefault(r, d, args)
+}
@@ -0,0 +1,97 @@
+package sllm
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+type uuid [16]byte
+
+func (u uuid) String() string { return "stringer" }
+
+type decimal struct {
+	units int64
+	scale int
+}
+
+func ExampleRenderType() {
+	var rs Renderers
+	RenderType(&rs, func(to []byte, u uuid) []byte {
+		return hex.AppendEncode(to, u[:4])
+	})
+	id := uuid{0xde, 0xad, 0xbe, 0xef}
+	Fprint(os.Stdout, "`id`\n", IdxArgs(id))
+	Fprint(os.Stdout, "`id`\n", rs.IdxArgs(id))
+	// Output:
+	// `id:stringer`
+	// `id:deadbeef`
+}
+
+func TestRenderers(t *testing.T) {
+	var rs Renderers
+	rs.Switch(func(to []byte, v any) ([]byte, bool) {
+		if d, ok := v.(decimal); ok {
+			return fmt.Appendf(to, "%de-%d", d.units, d.scale), true
+		}
+		return to, false
+	})
+	rs.Type(reflect.TypeOf(decimal{}), func(to []byte, v any) []byte {
+		return append(to, "type"...)
+	})
+	if s := string(rs.AppendArg(nil, decimal{1234, 2})); s != "type" {
+		t.Errorf("type renderer not preferred: '%s'", s)
+	}
+	if s := string(rs.AppendArg(nil, &decimal{1234, 2})); s != "type" {
+		t.Errorf("pointer not dereferenced: '%s'", s)
+	}
+	if s := string(AppendArg(nil, decimal{1234, 2})); s != "{1234 2}" {
+		t.Errorf("unexpected global rendering: '%s'", s)
+	}
+	if s := string(rs.AppendArg(nil, 7)); s != "7" {
+		t.Errorf("built-in type overridden: '%s'", s)
+	}
+
+	RenderType(&DefaultRenderers, func(to []byte, d decimal) []byte {
+		return append(to, "global"...)
+	})
+	defer DefaultRenderers.tab.Store(nil)
+	if s := string(AppendArg(nil, decimal{1234, 2})); s != "global" {
+		t.Errorf("global renderer not used: '%s'", s)
+	}
+	if s := string(rs.AppendArg(nil, decimal{1234, 2})); s != "type" {
+		t.Errorf("local renderer not preferred: '%s'", s)
+	}
+	buf, _ := Append(nil, "`a` `b`", rs.NmArgs(map[string]any{"a": decimal{}, "b": uuid{}}))
+	if s := string(buf); s != "`a:type` `b:stringer`" {
+		t.Errorf("unexpected message '%s'", s)
+	}
+}
+
+func TestRenderers_allocs(t *testing.T) {
+	var rs Renderers
+	RenderType(&rs, func(to []byte, u uuid) []byte { return hex.AppendEncode(to, u[:]) })
+	var arg any = uuid{1, 2, 3}
+	buf := make([]byte, 0, 64)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = rs.AppendArg(buf[:0], arg)
+	})
+	if allocs != 0 {
+		t.Errorf("%.1f allocations", allocs)
+	}
+}
+
+func TestRenderType_interface(t *testing.T) {
+	var rs Renderers
+	RenderType(&rs, func(to []byte, s fmt.Stringer) []byte {
+		return append(to, "iface"...)
+	})
+	if s := string(rs.AppendArg(nil, uuid{})); s != "iface" {
+		t.Errorf("interface renderer not used: '%s'", s)
+	}
+	if s := string(rs.AppendArg(nil, decimal{1234, 2})); s != "{1234 2}" {
+		t.Errorf("unexpected rendering: '%s'", s)
+	}
+}
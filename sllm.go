@@ -3,7 +3,6 @@ package sllm
 import (
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 )
 
@@ -18,6 +17,11 @@ const (
 // escape an argument implementers should use [EscString] or [EscBytes].
 type ArgsFunc func(buf []byte, i int, n string) ([]byte, error)
 
+// ValuesFunc returns the argument i with name n. Unlike an [ArgsFunc] it
+// returns the value itself instead of appending its text. This lets format
+// specs in templates apply to the typed value, see [AppendValues].
+type ValuesFunc func(i int, n string) (any, error)
+
 type ArgError struct {
 	Index int
 	Name  string
@@ -53,7 +57,36 @@ func (e ArgErrors) Error() string {
 
 func (e ArgErrors) Unwrap() []error { return e }
 
+// Append appends the message created from the template tmpl with the
+// arguments from args to the buffer to. Template parameters have the form
+// `name`, `name:idx` with an explicit argument index, `name:%spec` or
+// `name:idx%spec`, e.g. `latency:%.2f` or `mask:%x`. The format spec supports
+// the flags, width, precision and verbs of package fmt that apply to bools,
+// numbers and strings. The verb %T{flags} reformats a time with the
+// [TimeFormat] flags, e.g. `at:%T{TUTC|TMillis}`. The message still contains
+// `name:value`.
+//
+// As an ArgsFunc only appends text, Append applies format specs to the text
+// args appended, interpreted as required by the verb. The type of the
+// argument is lost, e.g. `mask:%x` formats the text "1234" as the number
+// 4d2. When args decorates another ArgsFunc, format specs apply to the
+// decorated text. Use [AppendValues] to apply format specs to typed values.
 func Append(to []byte, tmpl string, args ArgsFunc) ([]byte, error) {
+	return appendTmpl(to, tmpl, args, nil)
+}
+
+// AppendValues works like Append but gets the arguments from vals and appends
+// them with [AppendArg]. Format specs are applied to the typed values, e.g.
+// `mask:%x` formats the int 1234 as 4d2 and the string "1234" as 31323334.
+// Values of other types than bools, numbers, strings and []byte are
+// appended with AppendArg before the format spec is applied to their text.
+func AppendValues(to []byte, tmpl string, vals ValuesFunc) ([]byte, error) {
+	return appendTmpl(to, tmpl, nil, vals)
+}
+
+// appendTmpl implements Append and AppendValues. Exactly one of args and vals
+// must not be nil.
+func appendTmpl(to []byte, tmpl string, args ArgsFunc, vals ValuesFunc) ([]byte, error) {
 	var argErrs ArgErrors
 	argErr := func(i int, n string, err error) {
 		argErrs = append(argErrs, ArgError{Index: i, Name: n, Err: err})
@@ -76,26 +109,20 @@ func Append(to []byte, tmpl string, args ArgsFunc) ([]byte, error) {
 		if n == "" {
 			to = append(to, tmpl[:phnd]...)
 		} else {
-			var err error
-			if colon := strings.IndexByte(n, nameSepChar); colon >= 0 {
-				if colon == 0 {
-					return to, fmt.Errorf("empty parameter in '%s'", n)
-				}
-				idx, err := strconv.Atoi(n[colon+1:])
-				if err != nil {
-					return to, fmt.Errorf("index in '%s': %w", n, err)
-				}
-				to = append(to, tmpl[:phnd-len(n)+colon]...)
-				to = append(to, nameSepChar)
-				if to, err = args(to, idx, n[:colon]); err != nil {
-					argErr(argn, n, err)
-				}
-			} else {
-				to = append(to, tmpl[:phnd]...)
-				to = append(to, nameSepChar)
-				if to, err = args(to, argn, n); err != nil {
-					argErr(argn, n, err)
-				}
+			name, idx, hasIdx, f, err := splitParam(n)
+			if err != nil {
+				return to, err
+			}
+			to = append(to, tmpl[:phnd-len(n)+len(name)]...)
+			to = append(to, nameSepChar)
+			if !hasIdx {
+				idx = argn
+			}
+			to, err = f.appendArg(to, idx, name, args, vals)
+			if err != nil {
+				argErr(argn, n, err)
+			}
+			if !hasIdx {
 				argn++
 			}
 		}
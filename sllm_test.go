@@ -193,7 +193,7 @@ func ExampleAppend_time() {
 	os.Stdout.Write(buf)
 	fmt.Println()
 	// Output:
-	// `its:2023-11-27 21:30:00 +0000 UTC`
+	// `its:2023-11-27 Mo 21:30:00.000000+00`
 }
 
 func TestAppend_errors(t *testing.T) {
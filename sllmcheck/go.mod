@@ -0,0 +1,15 @@
+module git.fractalqb.de/fractalqb/sllm/v3/sllmcheck
+
+go 1.23.0
+
+require (
+	git.fractalqb.de/fractalqb/sllm/v3 v3.0.0
+	golang.org/x/tools v0.33.0
+)
+
+require (
+	golang.org/x/mod v0.24.0 // indirect
+	golang.org/x/sync v0.14.0 // indirect
+)
+
+replace git.fractalqb.de/fractalqb/sllm/v3 => ../
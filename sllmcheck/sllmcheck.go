@@ -0,0 +1,124 @@
+// Package sllmcheck provides a static analyzer that checks constant sllm
+// templates against the positional arguments passed with them.
+package sllmcheck
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const sllmPkg = "git.fractalqb.de/fractalqb/sllm/v3"
+
+// Analyzer reports mismatches between constant sllm templates and positional
+// arguments in calls to the sllm package.
+var Analyzer = &analysis.Analyzer{
+	Name:     "sllm",
+	Doc:      "check sllm templates against their positional arguments",
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+// Position of the template and the first argument in calls to functions with
+// variadic positional arguments.
+type idxFunc struct{ tmpl, args int }
+
+var idxFuncs = map[string]idxFunc{
+	"FprintIdx": {1, 2},
+	"StringIdx": {0, 1},
+	"ErrorIdx":  {0, 1},
+	"NewErr":    {0, 1},
+}
+
+// Position of the template and the ArgsFunc in calls to functions that take
+// an ArgsFunc.
+type argsFunc struct{ tmpl, args int }
+
+var argsFuncs = map[string]argsFunc{
+	"Append": {1, 2},
+	"Fprint": {1, 2},
+	"String": {0, 1},
+	"Error":  {0, 1},
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+	insp.Preorder([]ast.Node{(*ast.CallExpr)(nil)}, func(n ast.Node) {
+		call := n.(*ast.CallExpr)
+		fn := sllmFunc(pass, call)
+		if f, ok := idxFuncs[fn]; ok {
+			checkCall(pass, call, call.Args[f.tmpl], call, f.args, false)
+		} else if f, ok := argsFuncs[fn]; ok {
+			args, ok := call.Args[f.args].(*ast.CallExpr)
+			if !ok {
+				return
+			}
+			switch sllmFunc(pass, args) {
+			case "IdxArgs":
+				checkCall(pass, call, call.Args[f.tmpl], args, 0, false)
+			case "IdxArgsDefault":
+				checkCall(pass, call, call.Args[f.tmpl], args, 1, true)
+			}
+		}
+	})
+	return nil, nil
+}
+
+func sllmFunc(pass *analysis.Pass, call *ast.CallExpr) string {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != sllmPkg {
+		return ""
+	}
+	if sig, ok := fn.Type().(*types.Signature); !ok || sig.Recv() != nil {
+		return ""
+	}
+	return fn.Name()
+}
+
+func checkCall(pass *analysis.Pass, call *ast.CallExpr, tmplExpr ast.Expr, argCall *ast.CallExpr, argStart int, hasDefault bool) {
+	tv, ok := pass.TypesInfo.Types[tmplExpr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return
+	}
+	t, err := sllm.Compile(constant.StringVal(tv.Value))
+	if err != nil {
+		pass.Reportf(tmplExpr.Pos(), "invalid sllm template: %s", err)
+		return
+	}
+	if argCall.Ellipsis.IsValid() {
+		return
+	}
+	args := argCall.Args[argStart:]
+	used := make([]bool, len(args))
+	implicit := 0
+	for _, p := range t.ParamInfos(nil) {
+		if p.Index >= 0 && p.Index < len(args) {
+			used[p.Index] = true
+		}
+		if !p.Explicit {
+			implicit++
+		} else if (p.Index < 0 || p.Index >= len(args)) && !hasDefault {
+			pass.Reportf(tmplExpr.Pos(),
+				"explicit index %d of sllm parameter '%s' out of range of %d arguments",
+				p.Index, p.Name, len(args),
+			)
+		}
+	}
+	if implicit > len(args) && !hasDefault {
+		pass.Reportf(call.Pos(),
+			"sllm template has %d positional parameters but %d arguments",
+			implicit, len(args),
+		)
+	}
+	for i, u := range used {
+		if !u {
+			pass.Reportf(args[i].Pos(), "argument %d is not used by sllm template", i)
+		}
+	}
+}
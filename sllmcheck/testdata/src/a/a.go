@@ -0,0 +1,33 @@
+package a
+
+import (
+	"os"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+const cartTmpl = "added `count` ⨉ `item` to shopping cart by `user`"
+
+func calls(args []any) {
+	sllm.FprintIdx(os.Stdout, cartTmpl, 7, "Hat", "John Doe")
+	sllm.FprintIdx(os.Stdout, cartTmpl, 7, "Hat") // want `sllm template has 3 positional parameters but 2 arguments`
+	sllm.StringIdx("`a` and `b`", 1, 2, 3)        // want `argument 2 is not used by sllm template`
+	sllm.ErrorIdx("`a` and `b:3`", 1)             // want `explicit index 3 of sllm parameter 'b' out of range of 1 arguments`
+	sllm.StringIdx("unterminated `a", 1)          // want `invalid sllm template: unterminated parameter`
+	sllm.StringIdx("empty `:0` name", 1)          // want `invalid sllm template: empty parameter in ':0'`
+	sllm.StringIdx("`a:0` `b:0` tic ``", 1)
+	sllm.StringIdx("`a:%.2f` `b:0%x`", 1.5)
+	sllm.StringIdx("`a:%.2f` `b:%x`", 1.5) // want `sllm template has 2 positional parameters but 1 arguments`
+	sllm.StringIdx("`a:%T{TFoo}`", 1)      // want `invalid sllm template: format in 'a:%T\{TFoo\}': unknown time flag 'TFoo'`
+	sllm.StringIdx(cartTmpl, args...)
+	sllm.NewErr("`a` failed: `cause`", 1, nil)
+	sllm.NewErr("`a` failed: `cause`", 1) // want `sllm template has 2 positional parameters but 1 arguments`
+
+	sllm.Append(nil, "`a` `b`", sllm.IdxArgs(1))                        // want `sllm template has 2 positional parameters but 1 arguments`
+	sllm.Fprint(os.Stdout, "`a` `b:7`", sllm.IdxArgsDefault("-", 1, 2)) // want `argument 1 is not used by sllm template`
+	sllm.String("`a`", sllm.IdxArgs(1))
+	sllm.Error("`a`", nil)
+
+	tmpl := "`a`"
+	sllm.StringIdx(tmpl)
+}
@@ -0,0 +1,19 @@
+package sllm
+
+import "io"
+
+type ArgsFunc func(buf []byte, i int, n string) ([]byte, error)
+
+func Append(to []byte, tmpl string, args ArgsFunc) ([]byte, error) { return to, nil }
+func Fprint(w io.Writer, tmpl string, args ArgsFunc) (int, error)  { return 0, nil }
+func FprintIdx(w io.Writer, tmpl string, args ...any) (int, error) { return 0, nil }
+func String(tmpl string, args ArgsFunc) (string, error)            { return "", nil }
+func StringIdx(tmpl string, args ...any) (string, error)           { return "", nil }
+func Error(tmpl string, args ArgsFunc) error                       { return nil }
+func ErrorIdx(tmpl string, args ...any) error                      { return nil }
+func IdxArgs(args ...any) ArgsFunc                                 { return nil }
+func IdxArgsDefault(d any, args ...any) ArgsFunc                   { return nil }
+
+type Err struct{}
+
+func NewErr(tmpl string, args ...any) *Err { return nil }
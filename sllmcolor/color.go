@@ -0,0 +1,168 @@
+// Package sllmcolor highlights parameter names and arguments of sllm
+// messages with ANSI escape sequences.
+package sllmcolor
+
+import (
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// Theme defines the colors as SGR parameters, e.g. "1;34" for bold blue. An
+// empty string leaves the respective part uncolored.
+type Theme struct {
+	Name  string
+	Value string
+	// Error is used for the text of argument errors `name!(error)`.
+	Error string
+	// Values overrides Value for specific parameter names.
+	Values map[string]string
+}
+
+// Default is the theme used when nil is passed as *Theme.
+var Default = Theme{
+	Name:  "36",
+	Value: "1",
+	Error: "1;31",
+}
+
+func (t *Theme) valueSGR(name string) string {
+	if sgr, ok := t.Values[name]; ok {
+		return sgr
+	}
+	return t.Value
+}
+
+func appendColored(to []byte, sgr, s string) []byte {
+	if sgr == "" {
+		return Escape(to, s)
+	}
+	to = append(to, "\x1b["...)
+	to = append(to, sgr...)
+	to = append(to, 'm')
+	to = Escape(to, s)
+	return append(to, "\x1b[0m"...)
+}
+
+// Escape appends s to the buffer to with C0 and C1 control characters, DEL and
+// invalid UTF-8 bytes escaped as \xNN so that s cannot inject terminal escape
+// sequences. Tabs and newlines are kept.
+func Escape(to []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	for i := 0; i < len(s); {
+		r, n := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && n == 1:
+			r = rune(s[i])
+		case r < 0x20 && r != '\t' && r != '\n', r >= 0x7f && r <= 0x9f:
+		default:
+			to = append(to, s[i:i+n]...)
+			i += n
+			continue
+		}
+		to = append(to, '\\', 'x', hex[r>>4], hex[r&0xf])
+		i += n
+	}
+	return to
+}
+
+// Append parses the sllm message msg and appends it with colored parameter
+// names and arguments to the buffer to. msg is escaped with [Escape] to keep
+// messages from untrusted sources from controlling the terminal. If msg cannot be parsed, to is returned unchanged with the error.
+func (t *Theme) Append(to []byte, msg string) ([]byte, error) {
+	if t == nil {
+		t = &Default
+	}
+	start, last := len(to), 0
+	it := sllm.IterArgs(msg)
+	for name, arg := range it.All() {
+		nameStart := arg.Offset - len(name) - 1
+		if arg.IsError {
+			nameStart--
+		}
+		to = Escape(to, msg[last:nameStart])
+		to = appendColored(to, t.Name, name)
+		if arg.IsError {
+			to = append(to, "!("...)
+			to = appendColored(to, t.Error, arg.Value)
+		} else {
+			to = append(to, ':')
+			to = appendColored(to, t.valueSGR(name), arg.Value)
+		}
+		last = arg.Offset + len(arg.Value)
+	}
+	if err := it.Err(); err != nil {
+		return to[:start], err
+	}
+	return Escape(to, msg[last:]), nil
+}
+
+// Args wraps args so that the arguments are colored. As the parameter names
+// are written by [sllm.Append] itself, only arguments get colored.
+func (t *Theme) Args(args sllm.ArgsFunc) sllm.ArgsFunc {
+	if t == nil {
+		t = &Default
+	}
+	return func(buf []byte, i int, n string) ([]byte, error) {
+		sgr := t.valueSGR(n)
+		if sgr == "" {
+			return args(buf, i, n)
+		}
+		start := len(buf)
+		buf = append(buf, "\x1b["...)
+		buf = append(buf, sgr...)
+		buf = append(buf, 'm')
+		buf, err := args(buf, i, n)
+		if err != nil {
+			// Remove color start, copy may overlap
+			n := copy(buf[start:], buf[start+len(sgr)+3:])
+			return buf[:start+n], err
+		}
+		return append(buf, "\x1b[0m"...), nil
+	}
+}
+
+// IsTerminal reports whether w is a character device, e.g. a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	st, err := f.Stat()
+	return err == nil && st.Mode()&os.ModeCharDevice != 0
+}
+
+// Writer colorizes each chunk written to it as a sllm message if the
+// underlying writer is a terminal. Otherwise, data is passed through
+// unchanged. Chunks that are no valid sllm messages are written with [Escape].
+type Writer struct {
+	w     io.Writer
+	theme *Theme
+	tty   bool
+	buf   []byte
+}
+
+// NewWriter creates a Writer that writes to w using theme. theme may be nil
+// for the default theme.
+func NewWriter(w io.Writer, theme *Theme) *Writer {
+	return &Writer{w: w, theme: theme, tty: IsTerminal(w)}
+}
+
+// Write writes p, colorized if the underlying writer is a terminal. It
+// returns len(p) on success.
+func (w *Writer) Write(p []byte) (int, error) {
+	if !w.tty {
+		return w.w.Write(p)
+	}
+	var err error
+	w.buf, err = w.theme.Append(w.buf[:0], string(p))
+	if err != nil {
+		w.buf = Escape(w.buf[:0], string(p))
+	}
+	if _, err = w.w.Write(w.buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,77 @@
+package sllmcolor
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+func TestTheme_Append(t *testing.T) {
+	theme := Theme{Name: "N", Value: "V", Error: "E", Values: map[string]string{"x": "X", "y": ""}}
+	out, err := theme.Append(nil, "tic `` `a:1` and `x:a``b` `y:2` failed: `err!(missing)`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expect = "tic `` `\x1b[Nma\x1b[0m:\x1b[Vm1\x1b[0m` and " +
+		"`\x1b[Nmx\x1b[0m:\x1b[Xma``b\x1b[0m` `\x1b[Nmy\x1b[0m:2` failed: " +
+		"`\x1b[Nmerr\x1b[0m!(\x1b[Emmissing\x1b[0m)`"
+	if s := string(out); s != expect {
+		t.Errorf("unexpected output %q", s)
+	}
+	out, err = theme.Append([]byte("> "), "broken `a")
+	if err == nil || string(out) != "> " {
+		t.Errorf("unexpected result %q, %v", out, err)
+	}
+}
+
+func TestTheme_Append_control(t *testing.T) {
+	theme := Theme{Name: "N", Value: "V"}
+	out, err := theme.Append(nil, "\x1b[2J`a\x07:x\x1b]0;pwn\x07\u009b\xff\ty`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expect = "\\x1b[2J`\x1b[Nma\\x07\x1b[0m:\x1b[Vmx\\x1b]0;pwn\\x07\\x9b\\xff\ty\x1b[0m`"
+	if s := string(out); s != expect {
+		t.Errorf("unexpected output %q", s)
+	}
+}
+
+func TestTheme_Args(t *testing.T) {
+	theme := Theme{Value: "V", Values: map[string]string{"b": ""}}
+	out, _ := sllm.Append(nil, "`a` `b` `c`", theme.Args(sllm.IdxArgs(1, 2)))
+	const expect = "`a:\x1b[Vm1\x1b[0m` `b:2` `c!(missing argument 2 'c')`"
+	if s := string(out); s != expect {
+		t.Errorf("unexpected output %q", s)
+	}
+}
+
+func TestWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf, nil)
+	w.Write([]byte("`a:1`\n"))
+	if s := buf.String(); s != "`a:1`\n" {
+		t.Errorf("colored non-terminal output %q", s)
+	}
+	buf.Reset()
+	w.tty = true
+	n, err := w.Write([]byte("`a:1`\n"))
+	if err != nil || n != 6 {
+		t.Errorf("wrote %d, %v", n, err)
+	}
+	if s := buf.String(); !strings.Contains(s, "\x1b[") {
+		t.Errorf("not colored %q", s)
+	}
+}
+
+func TestTheme_Args_format(t *testing.T) {
+	out, err := sllm.Append(nil, "`d:%.1f` `s:%s`", Default.Args(sllm.IdxArgs(1.25, "x")))
+	if !errors.Is(err, sllm.ArgErrors{}) {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if s := string(out); !strings.HasPrefix(s, "`d!(format '%.1f'") || !strings.HasSuffix(s, "`s:\x1b[1mx\x1b[0m`") {
+		t.Errorf("unexpected output %q", s)
+	}
+}
@@ -0,0 +1,108 @@
+// Package sllmhtml renders sllm messages as HTML for web based log viewers.
+//
+// Each argument becomes a span element with the parameter name in the
+// data-name attribute, e.g.
+//
+//	added <span class="sllm-arg" data-name="count">7</span> x …
+//
+// Argument errors get the additional class [Renderer.ErrorClass]. Template
+// text, parameter names and arguments are HTML escaped.
+package sllmhtml
+
+import (
+	"html/template"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// Renderer defines the HTML markup of rendered messages.
+type Renderer struct {
+	// Class is the class of the span elements of arguments.
+	Class string
+	// ErrorClass is added to the class of argument errors `name!(error)`.
+	ErrorClass string
+	// If Markup is true, the sllm markup around the span elements and the
+	// escaped backticks are kept, i.e. `name:<span …>value</span>`.
+	Markup bool
+}
+
+// Default is the renderer used when nil is passed as *Renderer.
+var Default = Renderer{
+	Class:      "sllm-arg",
+	ErrorClass: "sllm-arg-error",
+}
+
+// Append parses the sllm message msg and appends it as HTML to the buffer to.
+// If msg cannot be parsed, to is returned unchanged with the error.
+func (r *Renderer) Append(to []byte, msg string) ([]byte, error) {
+	if r == nil {
+		r = &Default
+	}
+	start, last := len(to), 0
+	it := sllm.IterArgs(msg)
+	for name, arg := range it.All() {
+		nameStart := arg.Offset - len(name) - 2
+		if arg.IsError {
+			nameStart--
+		}
+		to = r.appendText(to, msg[last:nameStart])
+		if r.Markup {
+			to = appendEscaped(to, msg[nameStart:arg.Offset])
+		}
+		to = append(to, `<span class="`...)
+		to = appendEscaped(to, r.Class)
+		if arg.IsError && r.ErrorClass != "" {
+			to = append(to, ' ')
+			to = appendEscaped(to, r.ErrorClass)
+		}
+		to = append(to, `" data-name="`...)
+		to = appendEscaped(to, name)
+		to = append(to, `">`...)
+		to = r.appendText(to, arg.Value)
+		to = append(to, "</span>"...)
+		last = arg.Offset + len(arg.Value)
+		if !r.Markup {
+			last++ // closing '`'
+			if arg.IsError {
+				last++ // closing ')'
+			}
+		}
+	}
+	if err := it.Err(); err != nil {
+		return to[:start], err
+	}
+	return r.appendText(to, msg[last:]), nil
+}
+
+// HTML renders the sllm message msg for use with [html/template].
+func (r *Renderer) HTML(msg string) (template.HTML, error) {
+	h, err := r.Append(nil, msg)
+	return template.HTML(h), err
+}
+
+func (r *Renderer) appendText(to []byte, s string) []byte {
+	if !r.Markup {
+		s = sllm.UnescString(s)
+	}
+	return appendEscaped(to, s)
+}
+
+func appendEscaped(to []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '&':
+			to = append(to, "&amp;"...)
+		case '<':
+			to = append(to, "&lt;"...)
+		case '>':
+			to = append(to, "&gt;"...)
+		case '"':
+			to = append(to, "&#34;"...)
+		case '\'':
+			to = append(to, "&#39;"...)
+		default:
+			to = append(to, c)
+		}
+	}
+	return to
+}
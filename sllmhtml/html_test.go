@@ -0,0 +1,39 @@
+package sllmhtml
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleRenderer_Append() {
+	h, _ := Default.Append(nil, "added `count:7` x `item:<Hat>` for `user!(unknown)`")
+	fmt.Println(string(h))
+	// Output:
+	// added <span class="sllm-arg" data-name="count">7</span> x <span class="sllm-arg" data-name="item">&lt;Hat&gt;</span> for <span class="sllm-arg sllm-arg-error" data-name="user">unknown</span>
+}
+
+func TestRenderer_Append(t *testing.T) {
+	const msg = "a``b & `x:1``2` `\"q!(<e>)` end"
+	tests := []struct {
+		r      *Renderer
+		expect string
+	}{
+		{nil, `a` + "`" + `b &amp; <span class="sllm-arg" data-name="x">1` + "`" + `2</span> ` +
+			`<span class="sllm-arg sllm-arg-error" data-name="&#34;q">&lt;e&gt;</span> end`},
+		{&Renderer{Class: "c", Markup: true}, "a``b &amp; `x:" + `<span class="c" data-name="x">1` + "``" + `2</span>` + "` " +
+			"`&#34;q!(" + `<span class="c" data-name="&#34;q">&lt;e&gt;</span>` + ")` end"},
+	}
+	for _, test := range tests {
+		h, err := test.r.Append(nil, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := string(h); s != test.expect {
+			t.Errorf("unexpected HTML\n got: %s\nwant: %s", s, test.expect)
+		}
+	}
+	h, err := Default.Append([]byte("<p>"), "broken `a")
+	if err == nil || string(h) != "<p>" {
+		t.Errorf("unexpected result %q, %v", h, err)
+	}
+}
@@ -0,0 +1,208 @@
+// Package sllmi18n translates sllm messages into other languages while
+// keeping their machine-readable arguments.
+//
+// A [Catalog] maps canonical templates, i.e. the templates the messages were
+// created with, to localized templates. Localized templates may reorder
+// parameters. Parameters are matched by name. If a parameter occurs more than
+// once, the explicit index syntax `name:idx` selects the occurrence in the
+// canonical template. Otherwise, the first parameter with the name is used:
+//
+//	canonical: `user` sent `count` messages to `user`
+//	de:        `user:0` hat `user:2` `count` Nachrichten geschickt
+package sllmi18n
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// Catalog holds localized templates per locale. Locales are language tags
+// like "de" or "de-AT". The zero value is an empty catalog that is safe for
+// concurrent use.
+type Catalog struct {
+	mu      sync.RWMutex
+	locales map[string]map[string]entry
+}
+
+type entry struct {
+	tmpl string
+	t    *sllm.Template
+}
+
+// Add registers the localized template for the canonical template in locale.
+// Each parameter of localized must have the name of a parameter in canonical
+// and all parameters of canonical must be used.
+func (c *Catalog) Add(locale, canonical, localized string) error {
+	var cps []string
+	it := sllm.IterParams(canonical)
+	for _, n := range it.All() {
+		cps = append(cps, n)
+	}
+	if err := it.Err(); err != nil {
+		return fmt.Errorf("canonical template '%s': %w", canonical, err)
+	}
+	key, err := templateKey(canonical)
+	if err != nil {
+		return fmt.Errorf("canonical template '%s': %w", canonical, err)
+	}
+	t, err := sllm.Compile(localized)
+	if err == nil {
+		used := make([]bool, len(cps))
+		for _, p := range t.ParamInfos(nil) {
+			j := argIndex(cps, p)
+			if j < 0 {
+				err = fmt.Errorf("unknown parameter '%s'", p.Name)
+				break
+			}
+			used[j] = true
+		}
+		if err == nil {
+			if i := slices.Index(used, false); i >= 0 {
+				err = fmt.Errorf("missing parameter %d '%s'", i, cps[i])
+			}
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("%s template '%s': %w", locale, localized, err)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.locales == nil {
+		c.locales = make(map[string]map[string]entry)
+	}
+	tmpls := c.locales[locale]
+	if tmpls == nil {
+		tmpls = make(map[string]entry)
+		c.locales[locale] = tmpls
+	}
+	tmpls[key] = entry{localized, t}
+	return nil
+}
+
+// Lookup returns the localized template for the canonical template. If
+// locale has no such template, the parent locales are tried, e.g. "de" for
+// "de-AT".
+func (c *Catalog) Lookup(locale, canonical string) (string, bool) {
+	key, err := templateKey(canonical)
+	if err != nil {
+		return "", false
+	}
+	e, ok := c.lookup(locale, key)
+	return e.tmpl, ok
+}
+
+// templateKey returns the template that Parse reconstructs from messages
+// created with tmpl, i.e. tmpl without argument indices and format specs.
+// Translate looks up localized templates by this key.
+func templateKey(tmpl string) (string, error) {
+	msg, err := sllm.Append(nil, tmpl, func(buf []byte, _ int, _ string) ([]byte, error) {
+		return buf, nil
+	})
+	if _, ok := err.(sllm.ArgErrors); err != nil && !ok {
+		return "", err
+	}
+	var key bytes.Buffer
+	err = sllm.Parse(string(msg), &key, func(_, _ string, _ bool) error { return nil })
+	return key.String(), err
+}
+
+func (c *Catalog) lookup(locale, canonical string) (entry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	for {
+		if e, ok := c.locales[locale][canonical]; ok {
+			return e, true
+		}
+		i := strings.LastIndexAny(locale, "-_")
+		if i < 0 {
+			return entry{}, false
+		}
+		locale = locale[:i]
+	}
+}
+
+// Translate parses the sllm message msg and appends it rendered with the
+// localized template of locale to the buffer to. If the catalog has no
+// localized template, msg is appended unchanged. If msg cannot be parsed, to
+// is returned unchanged with the error.
+func (c *Catalog) Translate(to []byte, locale, msg string) ([]byte, error) {
+	var tmpl bytes.Buffer
+	args, err := parseArgs(msg, &tmpl)
+	if err != nil {
+		return to, err
+	}
+	e, ok := c.lookup(locale, tmpl.String())
+	if !ok {
+		return append(to, msg...), nil
+	}
+	return appendArgs(to, e.t, args)
+}
+
+// Render parses the sllm message msg and appends it rendered with template
+// tmpl to the buffer to. The parameters of tmpl are matched with the
+// arguments of msg as described for the package. Argument errors of msg are
+// kept. If msg cannot be parsed, to is returned unchanged with the error.
+func Render(to []byte, tmpl, msg string) ([]byte, error) {
+	t, err := sllm.Compile(tmpl)
+	if err != nil {
+		return to, err
+	}
+	args, err := parseArgs(msg, nil)
+	if err != nil {
+		return to, err
+	}
+	return appendArgs(to, t, args)
+}
+
+type arg struct {
+	name, value string
+	isErr       bool
+}
+
+func parseArgs(msg string, tmpl *bytes.Buffer) (args []arg, err error) {
+	err = sllm.Parse(msg, tmpl, func(name, value string, isErr bool) error {
+		args = append(args, arg{name, value, isErr})
+		return nil
+	})
+	return args, err
+}
+
+func appendArgs(to []byte, t *sllm.Template, args []arg) ([]byte, error) {
+	names := make([]string, len(args))
+	for i, a := range args {
+		names[i] = a.name
+	}
+	params := t.ParamInfos(nil)
+	k := 0
+	return t.Append(to, func(buf []byte, _ int, n string) ([]byte, error) {
+		i := argIndex(names, params[k])
+		k++
+		switch {
+		case i < 0:
+			return buf, fmt.Errorf("no argument '%s'", n)
+		case args[i].isErr:
+			return buf, argError(args[i].value)
+		}
+		// Values from Parse are still escaped
+		return append(buf, args[i].value...), nil
+	})
+}
+
+// argIndex returns the explicit index of p if names has p's name at that
+// index. Otherwise it returns the index of the first p.Name in names or -1.
+func argIndex(names []string, p sllm.ParamInfo) int {
+	if p.Explicit && p.Index >= 0 && p.Index < len(names) && names[p.Index] == p.Name {
+		return p.Index
+	}
+	return slices.Index(names, p.Name)
+}
+
+// argError keeps the text of an argument error from the original message.
+type argError string
+
+func (e argError) Error() string { return string(e) }
@@ -0,0 +1,108 @@
+package sllmi18n
+
+import (
+	"fmt"
+	"testing"
+)
+
+func ExampleCatalog_Translate() {
+	var cat Catalog
+	err := cat.Add("de",
+		"added `count` x `item` to shopping cart by `user`",
+		"`user` legte `count` x `item` in den Warenkorb",
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	msg := "added `count:7` x `item:Hat` to shopping cart by `user:John Doe`"
+	de, _ := cat.Translate(nil, "de-AT", msg)
+	fmt.Println(string(de))
+	fr, _ := cat.Translate(nil, "fr", msg)
+	fmt.Println(string(fr))
+	// Output:
+	// `user:John Doe` legte `count:7` x `item:Hat` in den Warenkorb
+	// added `count:7` x `item:Hat` to shopping cart by `user:John Doe`
+}
+
+func TestCatalog_Add(t *testing.T) {
+	const canonical = "`user` sent `count` messages to `user`"
+	tests := []struct {
+		localized string
+		err       string
+	}{
+		{"`user:0` hat `user:2` `count` Nachrichten geschickt", ""},
+		{"`count` Nachrichten von `user` an `user:2`", ""},
+		{"`user` an `user`: `count`", "de template '`user` an `user`: `count`': missing parameter 2 'user'"},
+		{"`user` `count`", "de template '`user` `count`': missing parameter 2 'user'"},
+		{"`count` `user` `user`", "de template '`count` `user` `user`': missing parameter 2 'user'"},
+		{"`user` `count` `foo` `user`", "de template '`user` `count` `foo` `user`': unknown parameter 'foo'"},
+		{"`user` `count` `user", "de template '`user` `count` `user': unterminated parameter"},
+	}
+	for _, test := range tests {
+		var cat Catalog
+		err := cat.Add("de", canonical, test.localized)
+		switch {
+		case test.err == "" && err != nil:
+			t.Errorf("%s: %s", test.localized, err)
+		case test.err != "" && (err == nil || err.Error() != test.err):
+			t.Errorf("%s: unexpected error %v", test.localized, err)
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		tmpl, msg, expect string
+	}{
+		{"`user:0` hat `user:2` `count` Nachrichten geschickt",
+			"`user:a``b` sent `count:3` messages to `user:c`",
+			"`user:a``b` hat `user:c` `count:3` Nachrichten geschickt"},
+		{"Fehler: `err` ``x``",
+			"failed: `err!(no such file)`",
+			"Fehler: `err!(no such file)` ``x``"},
+		{"`count` `user` `user`",
+			"`user:a` `count:1` `user:b`",
+			"`count:1` `user:a` `user:a`"},
+		{"`count` `user:2` `user`",
+			"`user:a` `count:1` `user:b`",
+			"`count:1` `user:b` `user:a`"},
+		{"`x` `y`", "`x:1`", "`x:1` `y!(no argument 'y')`"},
+	}
+	for _, test := range tests {
+		out, _ := Render(nil, test.tmpl, test.msg)
+		if s := string(out); s != test.expect {
+			t.Errorf("unexpected message\n got: %s\nwant: %s", s, test.expect)
+		}
+	}
+	out, err := Render([]byte("> "), "`x`", "broken `x")
+	if err == nil || string(out) != "> " {
+		t.Errorf("unexpected result %q, %v", out, err)
+	}
+}
+
+func TestCatalog_format(t *testing.T) {
+	var cat Catalog
+	if err := cat.Add("de", "took `d`", "dauerte `d:%.1f`"); err != nil {
+		t.Fatal(err)
+	}
+	out, err := cat.Translate(nil, "de", "took `d:1.25`")
+	if err != nil || string(out) != "dauerte `d:1.2`" {
+		t.Errorf("unexpected translation '%s', %v", out, err)
+	}
+}
+
+func TestCatalog_canonicalKey(t *testing.T) {
+	var cat Catalog
+	err := cat.Add("de", "took `d:%.2f` for `u:1` and `v:0`", "`v` brauchte `d` für `u`")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cat.Lookup("de", "took `d:%.2f` for `u:1` and `v:0`"); !ok {
+		t.Error("canonical template not found")
+	}
+	out, err := cat.Translate(nil, "de", "took `d:1.50` for `u:x` and `v:y`")
+	if err != nil || string(out) != "`v:y` brauchte `d:1.50` für `u:x`" {
+		t.Errorf("unexpected translation '%s', %v", out, err)
+	}
+}
@@ -0,0 +1,187 @@
+// Package sllmlogfmt converts sllm messages to logfmt key/value pairs and
+// back.
+//
+// A message is rendered as the pair msg="<template>" followed by a pair for
+// each argument in message order. Repeated parameters result in repeated keys.
+// Argument errors `name!(error)` become pairs with key "!name".
+package sllmlogfmt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// MsgKey is the logfmt key of the message template.
+const MsgKey = "msg"
+
+// Append parses the sllm message msg and appends it as logfmt to the buffer
+// to. If msg cannot be parsed, to is returned unchanged with the error.
+func Append(to []byte, msg string) ([]byte, error) {
+	var (
+		tmpl  bytes.Buffer
+		pairs []byte
+	)
+	err := sllm.Parse(msg, &tmpl, func(name, value string, isErr bool) error {
+		pairs = append(pairs, ' ')
+		if isErr {
+			pairs = append(pairs, '!')
+		}
+		pairs = AppendKey(pairs, name)
+		pairs = append(pairs, '=')
+		pairs = AppendValue(pairs, sllm.UnescString(value))
+		return nil
+	})
+	if err != nil {
+		return to, err
+	}
+	to = append(to, MsgKey...)
+	to = append(to, '=')
+	to = AppendValue(to, tmpl.String())
+	return append(to, pairs...), nil
+}
+
+// AppendKey appends key k to buffer to. Characters that are not allowed in
+// logfmt keys are replaced with '_'.
+func AppendKey(to []byte, k string) []byte {
+	if k == "" {
+		return append(to, '_')
+	}
+	for _, r := range k {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			r = '_'
+		}
+		to = utf8.AppendRune(to, r)
+	}
+	return to
+}
+
+// AppendValue appends v to buffer to. The value is quoted if it is empty or
+// contains spaces, '=', '"' or non-printable characters.
+func AppendValue(to []byte, v string) []byte {
+	if needsQuote(v) {
+		return strconv.AppendQuote(to, v)
+	}
+	return append(to, v...)
+}
+
+func needsQuote(v string) bool {
+	if v == "" {
+		return true
+	}
+	for _, r := range v {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Decode splits the logfmt line into key/value pairs and calls onPair for
+// each pair in order. Quoted values are unquoted. A key without '=' has the
+// empty value.
+func Decode(line string, onPair func(key, value string) error) error {
+	for {
+		line = trimSpace(line)
+		if line == "" {
+			return nil
+		}
+		i := 0
+		for i < len(line) && line[i] > ' ' && line[i] != '=' && line[i] != '"' {
+			i++
+		}
+		if i == 0 {
+			return fmt.Errorf("invalid key at '%s'", line)
+		}
+		key := line[:i]
+		line = line[i:]
+		var val string
+		if line != "" && line[0] == '=' {
+			line = line[1:]
+			if line != "" && line[0] == '"' {
+				q, err := strconv.QuotedPrefix(line)
+				if err != nil {
+					return fmt.Errorf("value of '%s': %w", key, err)
+				}
+				val, _ = strconv.Unquote(q)
+				line = line[len(q):]
+			} else {
+				i = 0
+				for i < len(line) && line[i] > ' ' {
+					i++
+				}
+				val, line = line[:i], line[i:]
+			}
+		}
+		if line != "" && line[0] > ' ' {
+			return fmt.Errorf("missing space after '%s'", key)
+		}
+		if err := onPair(key, val); err != nil {
+			return err
+		}
+	}
+}
+
+func trimSpace(s string) string {
+	for s != "" && s[0] <= ' ' {
+		s = s[1:]
+	}
+	return s
+}
+
+// AppendSllm creates a sllm message from the logfmt line and the template
+// tmpl with [sllm.Append] and appends it to the buffer to. If tmpl is empty,
+// the value of [MsgKey] from line is used as template. The occurrences of a
+// key are assigned to the occurrences of the parameter in order. If there are
+// less values than parameters, the last value is repeated. Pairs with keys
+// "!name" produce argument errors.
+func AppendSllm(to []byte, line, tmpl string) ([]byte, error) {
+	type value struct {
+		v     string
+		isErr bool
+	}
+	vals := make(map[string][]value)
+	err := Decode(line, func(key, v string) error {
+		if key == MsgKey && tmpl == "" {
+			tmpl = v
+			return nil
+		}
+		if k, ok := cutErrKey(key); ok {
+			vals[k] = append(vals[k], value{v, true})
+		} else {
+			vals[key] = append(vals[key], value{v, false})
+		}
+		return nil
+	})
+	if err != nil {
+		return to, err
+	}
+	if tmpl == "" {
+		return to, errors.New("no template")
+	}
+	used := make(map[string]int)
+	return sllm.Append(to, tmpl, func(buf []byte, i int, n string) ([]byte, error) {
+		vs := vals[n]
+		if len(vs) == 0 {
+			return buf, fmt.Errorf("missing argument %d '%s'", i, n)
+		}
+		u := min(used[n], len(vs)-1)
+		used[n]++
+		if vs[u].isErr {
+			return buf, errors.New(vs[u].v)
+		}
+		return sllm.EscString(buf, vs[u].v), nil
+	})
+}
+
+func cutErrKey(key string) (string, bool) {
+	if len(key) > 1 && key[0] == '!' {
+		return key[1:], true
+	}
+	return key, false
+}
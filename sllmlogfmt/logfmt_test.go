@@ -0,0 +1,74 @@
+package sllmlogfmt
+
+import (
+	"fmt"
+	"testing"
+)
+
+func Example() {
+	lf, _ := Append(nil, "added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`")
+	fmt.Println(string(lf))
+	msg, _ := AppendSllm(nil, string(lf), "")
+	fmt.Println(string(msg))
+	msg, _ = AppendSllm(nil, "level=info count=7 item=Hat", "`item` ⨉ `count`")
+	fmt.Println(string(msg))
+	// Output:
+	// msg="added `count` ⨉ `item` to shopping cart by `user`" count=7 item=Hat user="John Doe"
+	// added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`
+	// `item:Hat` ⨉ `count:7`
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, msg := range []string{
+		"no args",
+		"",
+		"`a:1` and `a:2` and `a:3`",
+		"`b:x``y` with `q:say \"hi\"` and `e!(missing argument)`",
+		"`sp: ` `eq:a=b` `bs:\\` `nl:\n` `empty:`",
+		"tic `` in template",
+	} {
+		lf, err := Append(nil, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		back, err := AppendSllm(nil, string(lf), "")
+		if msg == "" {
+			if err == nil {
+				t.Error("empty template not detected")
+			}
+			continue
+		}
+		if s := string(back); s != msg {
+			t.Errorf("'%s' → '%s' → '%s' (%v)", msg, lf, s, err)
+		}
+	}
+}
+
+func TestAppendSllm(t *testing.T) {
+	msg, err := AppendSllm(nil, `a=1 a=2`, "`a` `a` `a` `b`")
+	if s := string(msg); s != "`a:1` `a:2` `a:2` `b!(missing argument 3 'b')`" {
+		t.Errorf("unexpected message '%s'", s)
+	}
+	if err == nil {
+		t.Error("missing argument not reported")
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var out []string
+	err := Decode(` a=1 b="x y" flag c= d="q\"" `, func(k, v string) error {
+		out = append(out, k+":"+v)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := fmt.Sprint(out); s != `[a:1 b:x y flag: c: d:q"]` {
+		t.Errorf("unexpected pairs %s", s)
+	}
+	for _, line := range []string{`=x`, `a="x`, `a="x"y`} {
+		if err := Decode(line, func(_, _ string) error { return nil }); err == nil {
+			t.Errorf("no error for '%s'", line)
+		}
+	}
+}
@@ -0,0 +1,204 @@
+// Package sllmslog provides a [slog.Handler] that treats the message of a log
+// record as sllm template. The template parameters are filled from the
+// record's attributes.
+package sllmslog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// Attr is an attribute with its group-qualified name, e.g. "req.id" for
+// attribute "id" in group "req".
+type Attr struct {
+	Name  string
+	Value slog.Value
+}
+
+// TrailerFunc appends the attributes that are not referenced by the message
+// template to buf.
+type TrailerFunc func(buf []byte, attrs []Attr) []byte
+
+// ArgsTrailer returns a TrailerFunc that appends unreferenced attributes as
+// sllm arguments `name:value`, so they are found by sllm.Parse.
+func ArgsTrailer(tf sllm.TimeFormat) TrailerFunc {
+	return func(buf []byte, attrs []Attr) []byte {
+		for _, a := range attrs {
+			buf = append(buf, " `"...)
+			buf = append(buf, a.Name...)
+			buf = append(buf, ':')
+			buf = AppendValue(buf, a.Value, tf)
+			buf = append(buf, '`')
+		}
+		return buf
+	}
+}
+
+// NoTrailer drops all attributes not referenced by the message template.
+func NoTrailer(buf []byte, _ []Attr) []byte { return buf }
+
+// Options configure a Handler. The zero value is usable.
+type Options struct {
+	// Minimum level to log, default is slog.LevelInfo.
+	Level slog.Leveler
+	// Format of the record time and of time attributes.
+	TimeFormat sllm.TimeFormat
+	// Do not write the record time.
+	NoTime bool
+	// Appends unreferenced attributes, default is ArgsTrailer(TimeFormat).
+	Trailer TrailerFunc
+}
+
+// Handler is a [slog.Handler] that renders the record message as sllm template.
+type Handler struct {
+	out   io.Writer
+	mu    *sync.Mutex
+	opts  Options
+	attrs []Attr
+	group string
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler creates a Handler that writes to w. opts may be nil.
+func NewHandler(w io.Writer, opts *Options) *Handler {
+	h := &Handler{out: w, mu: new(sync.Mutex)}
+	if opts != nil {
+		h.opts = *opts
+	}
+	if h.opts.Level == nil {
+		h.opts.Level = slog.LevelInfo
+	}
+	if h.opts.Trailer == nil {
+		h.opts.Trailer = ArgsTrailer(h.opts.TimeFormat)
+	}
+	return h
+}
+
+func (h *Handler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= h.opts.Level.Level()
+}
+
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	res := *h
+	res.attrs = appendAttrs(slices.Clip(h.attrs), h.group, attrs...)
+	return &res
+}
+
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	res := *h
+	res.group = h.group + name + "."
+	return &res
+}
+
+var bufPool = sync.Pool{New: func() any { return new([]byte) }}
+
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	attrs := make([]Attr, len(h.attrs), len(h.attrs)+r.NumAttrs())
+	copy(attrs, h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = appendAttrs(attrs, h.group, a)
+		return true
+	})
+	used := make([]bool, len(attrs))
+
+	bp := bufPool.Get().(*[]byte)
+	defer bufPool.Put(bp)
+	buf := (*bp)[:0]
+	if !h.opts.NoTime && !r.Time.IsZero() {
+		buf = h.opts.TimeFormat.Append(buf, r.Time)
+		buf = append(buf, ' ')
+	}
+	buf = append(buf, r.Level.String()...)
+	buf = append(buf, ' ')
+	msgStart := len(buf)
+	buf, err := sllm.Append(buf, r.Message, func(buf []byte, _ int, n string) ([]byte, error) {
+		found := -1
+		for i := range attrs {
+			if attrs[i].Name == n {
+				used[i] = true
+				found = i
+			}
+		}
+		if found < 0 {
+			return buf, errors.New("missing attribute")
+		}
+		return AppendValue(buf, attrs[found].Value, h.opts.TimeFormat), nil
+	})
+	if err != nil && !errors.Is(err, sllm.ArgErrors{}) {
+		// Not a valid template: write the message as plain text
+		buf = sllm.EscString(buf[:msgStart], r.Message)
+		clear(used)
+	}
+	unused := attrs[:0]
+	for i, a := range attrs {
+		if !used[i] {
+			unused = append(unused, a)
+		}
+	}
+	buf = h.opts.Trailer(buf, unused)
+	buf = append(buf, '\n')
+	*bp = buf
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err = h.out.Write(buf)
+	return err
+}
+
+// AppendValue appends the escaped value v to buf. Time values are formatted
+// with tf.
+func AppendValue(buf []byte, v slog.Value, tf sllm.TimeFormat) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		return sllm.EscString(buf, v.String())
+	case slog.KindInt64:
+		return strconv.AppendInt(buf, v.Int64(), 10)
+	case slog.KindUint64:
+		return strconv.AppendUint(buf, v.Uint64(), 10)
+	case slog.KindFloat64:
+		return strconv.AppendFloat(buf, v.Float64(), 'f', -1, 64)
+	case slog.KindBool:
+		return strconv.AppendBool(buf, v.Bool())
+	case slog.KindDuration:
+		return sllm.EscString(buf, v.Duration().String())
+	case slog.KindTime:
+		return tf.Append(buf, v.Time())
+	case slog.KindLogValuer:
+		return AppendValue(buf, v.Resolve(), tf)
+	default:
+		return sllm.AppendArg(buf, v.Any())
+	}
+}
+
+func appendAttrs(to []Attr, prefix string, attrs ...slog.Attr) []Attr {
+	for _, a := range attrs {
+		v := a.Value.Resolve()
+		switch {
+		case v.Kind() == slog.KindGroup:
+			gp := prefix
+			if a.Key != "" {
+				gp += a.Key + "."
+			}
+			to = appendAttrs(to, gp, v.Group()...)
+		case a.Key == "":
+			continue
+		default:
+			to = append(to, Attr{Name: prefix + a.Key, Value: v})
+		}
+	}
+	return to
+}
@@ -0,0 +1,67 @@
+package sllmslog
+
+import (
+	"bytes"
+	"log/slog"
+	"os"
+	"testing"
+)
+
+func Example() {
+	log := slog.New(NewHandler(os.Stdout, &Options{NoTime: true}))
+	log = log.With("user", "John Doe").WithGroup("tx")
+	log.Info("added `tx.count` ⨉ `tx.item` to shopping cart by `user`",
+		"count", 7,
+		"item", "Hat",
+		"id", 4711,
+	)
+	// Output:
+	// INFO added `tx.count:7` ⨉ `tx.item:Hat` to shopping cart by `user:John Doe` `tx.id:4711`
+}
+
+func TestHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := slog.New(NewHandler(&buf, &Options{NoTime: true}))
+	test := func(t *testing.T, expect string) {
+		t.Helper()
+		if expect != "" {
+			expect += "\n"
+		}
+		if s := buf.String(); s != expect {
+			t.Errorf("unexpected output '%s'", s)
+		}
+		buf.Reset()
+	}
+	t.Run("plain", func(t *testing.T) {
+		log.Warn("`a` and `b`", "b", "x`y", "a", 1.5)
+		test(t, "WARN `a:1.5` and `b:x``y`")
+	})
+	t.Run("trailer", func(t *testing.T) {
+		log.Info("`a`", "a", true, "b", -3)
+		test(t, "INFO `a:true` `b:-3`")
+	})
+	t.Run("groups", func(t *testing.T) {
+		log.WithGroup("req").With("id", 4711).Info(
+			"request `req.id` by `req.usr.name`",
+			slog.Group("usr", "name", "John", "age", 42),
+		)
+		test(t, "INFO request `req.id:4711` by `req.usr.name:John` `req.usr.age:42`")
+	})
+	t.Run("missing", func(t *testing.T) {
+		log.Info("`a`")
+		test(t, "INFO `a!(missing attribute)`")
+	})
+	t.Run("no template", func(t *testing.T) {
+		log.Info("broken `tmpl", "a", 1)
+		test(t, "INFO broken ``tmpl `a:1`")
+	})
+	t.Run("level", func(t *testing.T) {
+		log.Debug("`a`", "a", 1)
+		test(t, "")
+	})
+	t.Run("no trailer", func(t *testing.T) {
+		log := slog.New(NewHandler(&buf, &Options{NoTime: true, Trailer: NoTrailer}))
+		log.Info("`a`", "a", 1, "b", 2)
+		test(t, "INFO `a:1`")
+	})
+}
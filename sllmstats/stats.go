@@ -0,0 +1,283 @@
+// Package sllmstats aggregates sllm messages by their reconstructed template.
+package sllmstats
+
+import (
+	"bytes"
+	"cmp"
+	"container/heap"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"git.fractalqb.de/fractalqb/sllm/v3"
+)
+
+// DefaultMaxValues is the number of distinct values tracked per parameter if
+// Stats.MaxValues is not set.
+const DefaultMaxValues = 1000
+
+// Stats collects statistics about a stream of sllm messages grouped by their
+// template. The zero value is ready to use.
+type Stats struct {
+	// MaxValues limits the number of distinct values tracked per parameter
+	// to bound the memory used for large logs. If 0, DefaultMaxValues is
+	// used. See Param.Top for the effect of the limit.
+	MaxValues int
+
+	tmpls map[string]*Template
+	seq   int
+	tmpl  bytes.Buffer
+	args  []arg
+}
+
+// Template holds the statistics of all messages with the same template.
+type Template struct {
+	Template    string
+	Count       int
+	First, Last Pos
+	Params      []*Param
+	seq         int
+}
+
+// Pos is the position of a message in the input.
+type Pos struct {
+	File string
+	Line int
+}
+
+func (p Pos) String() string {
+	if p.File == "" {
+		return strconv.Itoa(p.Line)
+	}
+	return p.File + ":" + strconv.Itoa(p.Line)
+}
+
+// Param holds the statistics of the arguments of one parameter name within
+// a template. Repeated parameters in a template are aggregated together.
+type Param struct {
+	Name     string
+	Count    int
+	ArgErrs  int
+	NumCount int // number of arguments that are decimal numbers
+	Min, Max float64
+	Sum      float64
+	// Capped is true if more than the maximum number of distinct values
+	// were seen. Then Cardinality and Top are approximations.
+	Capped bool
+	values map[string]*valueEntry
+	heap   valueHeap
+}
+
+type arg struct {
+	name, value string
+	isErr       bool
+}
+
+// ValueCount is an argument value with the number of its occurrences.
+type ValueCount struct {
+	Value string
+	Count int
+}
+
+// valueEntry is a tracked value with its position in the valueHeap.
+type valueEntry struct {
+	ValueCount
+	idx int
+}
+
+// Add parses msg found at pos and adds it to the statistics. If msg cannot be
+// parsed, it is not added. Stats does not keep references to msg.
+func (s *Stats) Add(pos Pos, msg string) error {
+	s.tmpl.Reset()
+	s.args = s.args[:0]
+	err := sllm.Parse(msg, &s.tmpl, func(name, value string, isErr bool) error {
+		s.args = append(s.args, arg{name, value, isErr})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if s.tmpls == nil {
+		s.tmpls = make(map[string]*Template)
+	}
+	t := s.tmpls[string(s.tmpl.Bytes())]
+	if t == nil {
+		t = &Template{Template: s.tmpl.String(), First: pos, seq: s.seq}
+		s.tmpls[t.Template] = t
+		s.seq++
+	}
+	t.Count++
+	t.Last = pos
+	maxVals := s.MaxValues
+	if maxVals <= 0 {
+		maxVals = DefaultMaxValues
+	}
+	for _, a := range s.args {
+		t.param(a.name).add(a, maxVals)
+	}
+	return nil
+}
+
+// Templates returns the statistics of all templates ordered by descending
+// count.
+func (s *Stats) Templates() []*Template {
+	res := make([]*Template, 0, len(s.tmpls))
+	for _, t := range s.tmpls {
+		res = append(res, t)
+	}
+	slices.SortFunc(res, func(a, b *Template) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.seq, b.seq)
+	})
+	return res
+}
+
+func (t *Template) param(name string) *Param {
+	for _, p := range t.Params {
+		if p.Name == name {
+			return p
+		}
+	}
+	p := &Param{Name: strings.Clone(name), Min: math.Inf(1), Max: math.Inf(-1)}
+	t.Params = append(t.Params, p)
+	return p
+}
+
+func (p *Param) add(a arg, maxVals int) {
+	p.Count++
+	if a.isErr {
+		p.ArgErrs++
+		return
+	}
+	if p.values == nil {
+		p.values = make(map[string]*valueEntry)
+	}
+	v := sllm.UnescString(a.value)
+	if vc := p.values[v]; vc != nil {
+		vc.Count++
+		heap.Fix(&p.heap, vc.idx)
+	} else if len(p.heap) < maxVals {
+		vc = &valueEntry{ValueCount: ValueCount{Value: strings.Clone(v), Count: 1}}
+		p.values[vc.Value] = vc
+		heap.Push(&p.heap, vc)
+	} else {
+		// Space-saving: the new value replaces the least frequent one and
+		// inherits its count, which makes counts upper bounds.
+		vc = p.heap[0]
+		delete(p.values, vc.Value)
+		vc.Value = strings.Clone(v)
+		vc.Count++
+		p.values[vc.Value] = vc
+		heap.Fix(&p.heap, 0)
+		p.Capped = true
+	}
+	if isDecimal(a.value) {
+		if f, err := strconv.ParseFloat(a.value, 64); err == nil {
+			p.NumCount++
+			p.Min = min(p.Min, f)
+			p.Max = max(p.Max, f)
+			p.Sum += f
+		}
+	}
+}
+
+// isDecimal reports whether s is a plain decimal number with optional sign,
+// fraction and exponent. Unlike strconv.ParseFloat it rejects "NaN", "Inf"
+// and hexadecimal numbers.
+func isDecimal(s string) bool {
+	if s != "" && (s[0] == '+' || s[0] == '-') {
+		s = s[1:]
+	}
+	digits := func() int {
+		i := 0
+		for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			i++
+		}
+		s = s[i:]
+		return i
+	}
+	n := digits()
+	if s != "" && s[0] == '.' {
+		s = s[1:]
+		n += digits()
+	}
+	if n == 0 {
+		return false
+	}
+	if s != "" && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s != "" && (s[0] == '+' || s[0] == '-') {
+			s = s[1:]
+		}
+		if digits() == 0 {
+			return false
+		}
+	}
+	return s == ""
+}
+
+// Cardinality returns the number of distinct argument values. If p is Capped,
+// it is the maximum number of tracked values.
+func (p *Param) Cardinality() int { return len(p.values) }
+
+// IsNumeric reports whether all non-error arguments parsed as numbers.
+func (p *Param) IsNumeric() bool {
+	return p.NumCount > 0 && p.NumCount == p.Count-p.ArgErrs
+}
+
+// Mean returns the mean of the numeric arguments.
+func (p *Param) Mean() float64 {
+	if p.NumCount == 0 {
+		return math.NaN()
+	}
+	return p.Sum / float64(p.NumCount)
+}
+
+// Top returns the n most frequent argument values, ordered by descending
+// count. If p is Capped, the values are tracked with the space-saving
+// algorithm: Values that occur more often than Count/MaxValues are
+// guaranteed to be included but their counts may be too high.
+func (p *Param) Top(n int) []ValueCount {
+	res := make([]ValueCount, 0, len(p.values))
+	for _, vc := range p.values {
+		res = append(res, vc.ValueCount)
+	}
+	slices.SortFunc(res, func(a, b ValueCount) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Value, b.Value)
+	})
+	if len(res) > n {
+		res = res[:n]
+	}
+	return res
+}
+
+// valueHeap is a min-heap of value counts used to find the least frequent
+// value when the number of tracked values is capped.
+type valueHeap []*valueEntry
+
+func (h valueHeap) Len() int           { return len(h) }
+func (h valueHeap) Less(i, j int) bool { return h[i].Count < h[j].Count }
+
+func (h valueHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].idx, h[j].idx = i, j
+}
+
+func (h *valueHeap) Push(x any) {
+	vc := x.(*valueEntry)
+	vc.idx = len(*h)
+	*h = append(*h, vc)
+}
+
+func (h *valueHeap) Pop() any {
+	old := *h
+	vc := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return vc
+}
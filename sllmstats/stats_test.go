@@ -0,0 +1,101 @@
+package sllmstats
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func Example() {
+	var stats Stats
+	for i, msg := range []string{
+		"added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`",
+		"`user:John Doe` logged in",
+		"added `count:2` ⨉ `item:Shoe` to shopping cart by `user:Jane`",
+		"added `count:3` ⨉ `item:Hat` to shopping cart by `user:John Doe`",
+	} {
+		stats.Add(Pos{Line: i + 1}, msg)
+	}
+	for _, t := range stats.Templates() {
+		fmt.Printf("%d [%s-%s] %s\n", t.Count, t.First, t.Last, t.Template)
+		for _, p := range t.Params {
+			fmt.Printf("  %s: %d values, top %v", p.Name, p.Cardinality(), p.Top(1))
+			if p.IsNumeric() {
+				fmt.Printf(", min %g max %g mean %g", p.Min, p.Max, p.Mean())
+			}
+			fmt.Println()
+		}
+	}
+	// Output:
+	// 3 [1-4] added `count` ⨉ `item` to shopping cart by `user`
+	//   count: 3 values, top [{2 1}], min 2 max 7 mean 4
+	//   item: 2 values, top [{Hat 2}]
+	//   user: 2 values, top [{John Doe 2}]
+	// 1 [2-2] `user` logged in
+	//   user: 1 values, top [{John Doe 1}]
+}
+
+func TestStats(t *testing.T) {
+	var stats Stats
+	if err := stats.Add(Pos{"a", 1}, "broken `arg"); err == nil {
+		t.Error("parse error not detected")
+	}
+	stats.Add(Pos{"a", 2}, "`a:1` and `a:x` with `e!(failed)` and `b:a``b`")
+	stats.Add(Pos{"b", 1}, "`a:3` and `a:x` with `e:2` and `b:a``b`")
+	ts := stats.Templates()
+	if len(ts) != 1 {
+		t.Fatalf("%d templates", len(ts))
+	}
+	tmpl := ts[0]
+	if p := fmt.Sprintf("%s %s", tmpl.First, tmpl.Last); p != "a:2 b:1" {
+		t.Errorf("wrong positions %s", p)
+	}
+	if tmpl.Template != "`a` and `a` with `e` and `b`" {
+		t.Errorf("wrong template '%s'", tmpl.Template)
+	}
+	var sb strings.Builder
+	for _, p := range tmpl.Params {
+		fmt.Fprintf(&sb, "%s %d %d %d %t %v;", p.Name, p.Count, p.ArgErrs, p.Cardinality(), p.IsNumeric(), p.Top(5))
+	}
+	const expect = "a 4 0 3 false [{x 2} {1 1} {3 1}];e 2 1 1 true [{2 1}];b 2 0 1 false [{a`b 2}];"
+	if s := sb.String(); s != expect {
+		t.Errorf("unexpected params: %s", s)
+	}
+}
+
+func TestStats_maxValues(t *testing.T) {
+	stats := Stats{MaxValues: 2}
+	for i, v := range []string{"a", "b", "a", "c", "a", "d", "a"} {
+		stats.Add(Pos{Line: i + 1}, "`v:"+v+"`")
+	}
+	p := stats.Templates()[0].Params[0]
+	if !p.Capped || p.Cardinality() != 2 {
+		t.Errorf("capped %t with %d values", p.Capped, p.Cardinality())
+	}
+	if top := fmt.Sprint(p.Top(1)); top != "[{a 4}]" {
+		t.Errorf("unexpected top %s", top)
+	}
+}
+
+func TestStats_numeric(t *testing.T) {
+	var stats Stats
+	for i, v := range []string{"1", "-2.5e1", "Nan", "Inf", "0x10"} {
+		stats.Add(Pos{Line: i + 1}, "`n:"+v+"`")
+	}
+	p := stats.Templates()[0].Params[0]
+	if p.NumCount != 2 || p.IsNumeric() || p.Mean() != -12 {
+		t.Errorf("%d numbers, numeric %t, mean %g", p.NumCount, p.IsNumeric(), p.Mean())
+	}
+}
+
+func Test_isDecimal(t *testing.T) {
+	for s, expect := range map[string]bool{
+		"0": true, "-1": true, "+1.5": true, ".5": true, "5.": true, "1e3": true, "1E-3": true,
+		"": false, "-": false, ".": false, "e3": false, "1e": false, "NaN": false,
+		"Inf": false, "0x1p3": false, "1_000": false, "1.2.3": false,
+	} {
+		if isDecimal(s) != expect {
+			t.Errorf("isDecimal(%q) != %t", s, expect)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package sllm
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// StructArgs resolves template parameters against the exported fields of the
+// struct v, which may also be a pointer to a struct. The parameter name of a
+// field is set with the struct tag `sllm:"name"` and defaults to the field
+// name. A field tagged with `sllm:"-"` is ignored. Fields of nested structs are
+// referenced with dotted names, e.g. "req.id". Fields of embedded structs are
+// promoted unless the embedded field has a tag name. With the tag option
+// omitempty, e.g. `sllm:"name,omitempty"`, a field with zero value is treated
+// as missing.
+//
+// The lookup plan for a struct type is computed once and cached.
+func StructArgs(v any) ArgsFunc {
+	return structArgs(v, func(buf []byte, i int, n string) ([]byte, error) {
+		return buf, fmt.Errorf("missing argument %d '%s'", i, n)
+	})
+}
+
+// StructArgsDefault is like StructArgs but uses d for missing arguments.
+func StructArgsDefault(d any, v any) ArgsFunc {
+	return structArgs(v, func(buf []byte, _ int, _ string) ([]byte, error) {
+		return AppendArg(buf, d), nil
+	})
+}
+
+func structArgs(v any, missing ArgsFunc) ArgsFunc {
+	sv := reflect.ValueOf(v)
+	for sv.Kind() == reflect.Pointer {
+		if sv.IsNil() {
+			return missing
+		}
+		sv = sv.Elem()
+	}
+	if sv.Kind() != reflect.Struct {
+		err := fmt.Errorf("%T is not a struct", v)
+		return func(buf []byte, _ int, _ string) ([]byte, error) {
+			return buf, err
+		}
+	}
+	plan := structPlanOf(sv.Type())
+	return func(buf []byte, i int, n string) ([]byte, error) {
+		f, ok := plan[n]
+		if !ok {
+			return missing(buf, i, n)
+		}
+		fv, ok := f.value(sv)
+		if !ok {
+			return missing(buf, i, n)
+		}
+		return AppendArg(buf, fv.Interface()), nil
+	}
+}
+
+type structPlan map[string]fieldPlan
+
+type fieldPlan struct {
+	index     []int
+	omitEmpty bool
+}
+
+func (f fieldPlan) value(sv reflect.Value) (reflect.Value, bool) {
+	for i, x := range f.index {
+		if i > 0 {
+			for sv.Kind() == reflect.Pointer {
+				if sv.IsNil() {
+					return sv, false
+				}
+				sv = sv.Elem()
+			}
+		}
+		sv = sv.Field(x)
+	}
+	if f.omitEmpty && sv.IsZero() {
+		return sv, false
+	}
+	return sv, true
+}
+
+var structPlans sync.Map // reflect.Type → structPlan
+
+func structPlanOf(t reflect.Type) structPlan {
+	if p, ok := structPlans.Load(t); ok {
+		return p.(structPlan)
+	}
+	p := make(structPlan)
+	addStructFields(p, t, "", nil, map[reflect.Type]bool{t: true})
+	structPlans.Store(t, p)
+	return p
+}
+
+func addStructFields(p structPlan, t reflect.Type, prefix string, index []int, path map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, omitEmpty, skip := fieldTag(f)
+		if skip || !(f.IsExported() || f.Anonymous) {
+			continue
+		}
+		fidx := append(index[:len(index):len(index)], i)
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct && !path[ft] && !isArgType(f.Type) {
+			path[ft] = true
+			if f.Anonymous && name == "" {
+				addStructFields(p, ft, prefix, fidx, path)
+				delete(path, ft)
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			addStructFields(p, ft, prefix+name+".", fidx, path)
+			delete(path, ft)
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		name = prefix + name
+		if fp, ok := p[name]; !ok || len(fidx) < len(fp.index) {
+			p[name] = fieldPlan{index: fidx, omitEmpty: omitEmpty}
+		}
+	}
+}
+
+func fieldTag(f reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := f.Tag.Get("sllm")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	for opts != "" {
+		var opt string
+		opt, opts, _ = strings.Cut(opts, ",")
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
+
+var (
+	appenderType = reflect.TypeOf((*Appender)(nil)).Elem()
+	stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+)
+
+// isArgType reports whether a struct type has its own rendering with
+// AppendArg and shall not be decomposed into nested parameters.
+func isArgType(t reflect.Type) bool {
+	return t.Implements(appenderType) || t.Implements(stringerType)
+}
@@ -0,0 +1,105 @@
+package sllm
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func ExampleStructArgs() {
+	type cart struct {
+		Count int
+		Item  string `sllm:"item"`
+		User  struct {
+			Name string `sllm:"name"`
+		} `sllm:"user"`
+	}
+	var c cart
+	c.Count = 7
+	c.Item = "Hat"
+	c.User.Name = "John Doe"
+	Fprint(os.Stdout, "added `Count` ⨉ `item` to shopping cart by `user.name`\n",
+		StructArgs(&c),
+	)
+	// Output:
+	// added `Count:7` ⨉ `item:Hat` to shopping cart by `user.name:John Doe`
+}
+
+type testStructInner struct {
+	ID   int `sllm:"id"`
+	Note string
+}
+
+type testStructArgs struct {
+	testStructInner
+	Name    string           `sllm:"name"`
+	Skip    string           `sllm:"-"`
+	Opt     int              `sllm:"opt,omitempty"`
+	Req     *testStructInner `sllm:"req"`
+	At      time.Time        `sllm:"at"`
+	Self    *testStructArgs
+	private int
+}
+
+func TestStructArgs(t *testing.T) {
+	v := testStructArgs{
+		testStructInner: testStructInner{ID: 4711, Note: "n"},
+		Name:            "John",
+		Skip:            "skip",
+		Req:             &testStructInner{ID: 3},
+		At:              time.Date(2023, 11, 27, 21, 30, 0, 0, time.UTC),
+		private:         1,
+	}
+	test := func(t *testing.T, args ArgsFunc, tmpl, expect string) {
+		t.Helper()
+		out, _ := Append(nil, tmpl, args)
+		if s := string(out); s != expect {
+			t.Errorf("expect '%s', got '%s'", expect, s)
+		}
+	}
+	t.Run("fields", func(t *testing.T) {
+		test(t, StructArgs(v), "`id` `Note` `name` `req.id` `req.Note`",
+			"`id:4711` `Note:n` `name:John` `req.id:3` `req.Note:`")
+	})
+	t.Run("not decomposed", func(t *testing.T) {
+		test(t, StructArgs(v), "`at`", "`at:2023-11-27 Mo 21:30:00.000000+00`")
+	})
+	t.Run("missing", func(t *testing.T) {
+		test(t, StructArgs(&v), "`Skip` `opt` `private` `Self.name`",
+			"`Skip!(missing argument 0 'Skip')` `opt!(missing argument 1 'opt')` "+
+				"`private!(missing argument 2 'private')` `Self.name!(missing argument 3 'Self.name')`")
+	})
+	t.Run("default", func(t *testing.T) {
+		test(t, StructArgsDefault("-", v), "`opt` `nope`", "`opt:-` `nope:-`")
+	})
+	t.Run("omitempty set", func(t *testing.T) {
+		v := v
+		v.Opt = 9
+		test(t, StructArgs(v), "`opt`", "`opt:9`")
+	})
+	t.Run("nil pointer", func(t *testing.T) {
+		test(t, StructArgsDefault("-", (*testStructArgs)(nil)), "`name`", "`name:-`")
+	})
+	t.Run("no struct", func(t *testing.T) {
+		test(t, StructArgs(4711), "`a`", "`a!(int is not a struct)`")
+	})
+}
+
+func BenchmarkAppend_structArgs(b *testing.B) {
+	type args struct {
+		Service string        `sllm:"service"`
+		Signal  string        `sllm:"signal"`
+		Process int           `sllm:"process"`
+		Name    string        `sllm:"name"`
+		At      timeFormatter `sllm:"at"`
+	}
+	v := args{testSvc, testSig, testProc, testName, testNow}
+	var buf []byte
+	outBytes = 0
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		buf, _ = Append(buf, testTmpl, StructArgs(&v))
+		outBytes += len(buf)
+	}
+}
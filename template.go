@@ -0,0 +1,170 @@
+package sllm
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Template is a precompiled sllm template. Compiling a template once and
+// reusing it avoids rescanning the template string on every call to Append.
+type Template struct {
+	params []tmplParam
+	tail   string
+}
+
+type tmplParam struct {
+	prefix   string // literal text up to and including "`name:"
+	name     string
+	idx      int // argument index passed to ArgsFunc
+	explicit bool
+	format   argFormat
+	errIdx   int // index reported in ArgError
+	errName  string
+}
+
+// Compile parses the template tmpl. Errors in the template, e.g. unterminated
+// or empty parameters, are reported by Compile and will not show up when using
+// the Template.
+func Compile(tmpl string) (*Template, error) {
+	var (
+		t    Template
+		lit  strings.Builder
+		argn int
+	)
+	phst := strings.IndexByte(tmpl, tmplEscChar)
+	for phst >= 0 {
+		phst++
+		phnd := strings.IndexByte(tmpl[phst:], tmplEscChar)
+		if phnd < 0 {
+			return nil, errors.New("unterminated parameter")
+		}
+		phnd += phst
+		n := tmpl[phst:phnd]
+		if n == "" {
+			lit.WriteString(tmpl[:phnd])
+		} else {
+			name, idx, hasIdx, f, err := splitParam(n)
+			if err != nil {
+				return nil, err
+			}
+			if !hasIdx {
+				idx = argn
+			}
+			lit.WriteString(tmpl[:phnd-len(n)+len(name)])
+			lit.WriteByte(nameSepChar)
+			t.params = append(t.params, tmplParam{
+				prefix:   lit.String(),
+				name:     name,
+				idx:      idx,
+				explicit: hasIdx,
+				format:   f,
+				errIdx:   argn,
+				errName:  n,
+			})
+			lit.Reset()
+			if !hasIdx {
+				argn++
+			}
+		}
+		lit.WriteByte(tmplEscChar)
+		tmpl = tmpl[phnd+1:]
+		phst = strings.IndexByte(tmpl, tmplEscChar)
+	}
+	lit.WriteString(tmpl)
+	t.tail = lit.String()
+	return &t, nil
+}
+
+// MustCompile is like Compile but panics if the template cannot be compiled.
+func MustCompile(tmpl string) *Template {
+	t, err := Compile(tmpl)
+	if err != nil {
+		panic(fmt.Errorf("sllm: compile '%s': %w", tmpl, err))
+	}
+	return t
+}
+
+// Append works like the package level function Append but uses the
+// precompiled template t. The only errors that can occur are [ArgErrors].
+func (t *Template) Append(to []byte, args ArgsFunc) ([]byte, error) {
+	return t.append(to, args, nil)
+}
+
+// AppendValues works like the package level function AppendValues but uses
+// the precompiled template t. The only errors that can occur are [ArgErrors].
+func (t *Template) AppendValues(to []byte, vals ValuesFunc) ([]byte, error) {
+	return t.append(to, nil, vals)
+}
+
+func (t *Template) append(to []byte, args ArgsFunc, vals ValuesFunc) ([]byte, error) {
+	var (
+		argErrs ArgErrors
+		err     error
+	)
+	for _, p := range t.params {
+		to = append(to, p.prefix...)
+		sep := len(to) - 1
+		if to, err = p.format.appendArg(to, p.idx, p.name, args, vals); err != nil {
+			argErrs = append(argErrs, ArgError{Index: p.errIdx, Name: p.errName, Err: err})
+			to[sep] = argErrChar
+			to = append(to, '(')
+			to = append(to, err.Error()...)
+			to = append(to, ')')
+		}
+	}
+	to = append(to, t.tail...)
+	if len(argErrs) > 0 {
+		return to, argErrs
+	}
+	return to, nil
+}
+
+// Parameters appends the parameter names of t to a.
+func (t *Template) Parameters(a []string) []string {
+	for _, p := range t.params {
+		a = append(a, p.name)
+	}
+	return a
+}
+
+// ParamInfo describes a parameter of a [Template].
+type ParamInfo struct {
+	Name string
+	// Index is the argument index passed to the ArgsFunc.
+	Index int
+	// Explicit is true if Index is set in the template, i.e. `name:idx`.
+	Explicit bool
+	// Format is the format spec of the parameter, e.g. "%.2f", if any.
+	Format string
+}
+
+// ParamInfos appends the descriptions of the parameters of t to a.
+func (t *Template) ParamInfos(a []ParamInfo) []ParamInfo {
+	for _, p := range t.params {
+		a = append(a, ParamInfo{
+			Name:     p.name,
+			Index:    p.idx,
+			Explicit: p.explicit,
+			Format:   p.format.spec,
+		})
+	}
+	return a
+}
+
+// Fprint works like the package level function Fprint but uses the
+// precompiled template t. Argument errors are part of the written message and
+// are not returned.
+func (t *Template) Fprint(w io.Writer, args ArgsFunc) (int, error) {
+	if buf, ok := w.(*bytes.Buffer); ok {
+		l := buf.Len()
+		tmp, _ := t.Append(buf.Bytes(), args)
+		buf.Reset()
+		buf.Write(tmp[:l])
+		return buf.Write(tmp[l:])
+	}
+	tmp, _ := t.Append(nil, args)
+	return w.Write(tmp)
+}
@@ -0,0 +1,113 @@
+package sllm
+
+import (
+	"bytes"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func ExampleTemplate() {
+	tmpl := MustCompile("added `count` ⨉ `item` to shopping cart by `user`\n")
+	tmpl.Fprint(os.Stdout, IdxArgs(7, "Hat", "John Doe"))
+	// Output:
+	// added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`
+}
+
+func TestTemplate_sameAsAppend(t *testing.T) {
+	args := IdxArgs(4711, "foo`bar", true)
+	for _, tmpl := range []string{
+		"",
+		"no params",
+		"`a`",
+		"tic `` in `a` text",
+		"`a` and `b:0` and `c`",
+		"touching `a``b``c`",
+		"missing `a` `b` `c` `d`",
+		"missing `a:7` and `b`",
+	} {
+		t.Run(tmpl, func(t *testing.T) {
+			expect, experr := Append(nil, tmpl, args)
+			ct, err := Compile(tmpl)
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := ct.Append(nil, args)
+			if !bytes.Equal(out, expect) {
+				t.Errorf("expect '%s', got '%s'", string(expect), string(out))
+			}
+			if !reflect.DeepEqual(err, experr) {
+				t.Errorf("expect error '%v', got '%v'", experr, err)
+			}
+		})
+	}
+}
+
+func TestTemplate_Parameters(t *testing.T) {
+	ct := MustCompile("`a` and `b:0` with `` and `c`")
+	ps := ct.Parameters(nil)
+	if !reflect.DeepEqual(ps, []string{"a", "b", "c"}) {
+		t.Fatal("wrong params:", ps)
+	}
+}
+
+func TestCompile_errors(t *testing.T) {
+	for tmpl, msg := range map[string]string{
+		"this `is unterminated": "unterminated parameter",
+		"without end `":         "unterminated parameter",
+		"illegal `place:`":      "index in 'place:': strconv.Atoi: parsing \"\": invalid syntax",
+		"foo `:7` baz":          "empty parameter in ':7'",
+	} {
+		t.Run(tmpl, func(t *testing.T) {
+			_, err := Compile(tmpl)
+			if err == nil {
+				t.Fatal("no error")
+			}
+			if err.Error() != msg {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestTemplate_Fprint(t *testing.T) {
+	ct := MustCompile("`a` and `b`")
+	var buf bytes.Buffer
+	buf.WriteString("prefix: ")
+	n, err := ct.Fprint(&buf, IdxArgs(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	const expect = "prefix: `a:1` and `b!(missing argument 1 'b')`"
+	if s := buf.String(); s != expect {
+		t.Errorf("unexpected output '%s'", s)
+	}
+	if n != len(expect)-8 {
+		t.Errorf("wrong byte count %d", n)
+	}
+}
+
+func BenchmarkTemplate_testArgsN(b *testing.B) {
+	ct := MustCompile(testTmpl)
+	var buf []byte
+	outBytes = 0
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		buf, _ = ct.Append(buf, testArgsN)
+		outBytes += len(buf)
+	}
+}
+
+func TestTemplate_ParamInfos(t *testing.T) {
+	tmpl := MustCompile("`a` `b:3` `c:%x` `d:0%d`")
+	infos := tmpl.ParamInfos(nil)
+	expect := []ParamInfo{
+		{Name: "a", Index: 0},
+		{Name: "b", Index: 3, Explicit: true},
+		{Name: "c", Index: 1, Format: "%x"},
+		{Name: "d", Index: 0, Explicit: true, Format: "%d"},
+	}
+	if !reflect.DeepEqual(infos, expect) {
+		t.Errorf("unexpected infos %+v", infos)
+	}
+}
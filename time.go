@@ -1,7 +1,7 @@
 package sllm
 
 import (
-	"math"
+	"fmt"
 	"time"
 )
 
@@ -13,7 +13,108 @@ func (tf TimeFormat) Append(buf []byte, t time.Time) []byte {
 	return tf.Fmt(t).AppendSllm(buf)
 }
 
-// TODO: TimeFormat.Parse()
+// Parse parses the string s that was formatted with tf. Date components that
+// are not part of the format are taken from ref. When the year is missing, the
+// year that puts the result closest to ref is chosen. When the weekday is
+// present, it must agree with the date. Without TUTC the parsed time zone
+// offset is used as location, with TUTC the result is in UTC.
+func (tf TimeFormat) Parse(s string, ref time.Time) (time.Time, error) {
+	var (
+		p                  = timeParser{s: s}
+		ye, dy             int
+		mo                 time.Month
+		ho, mi, sc, ns     int
+		wd                 string
+		loc                = time.UTC
+		needTime, needDate = tf.allOff(TNoClock), tf.allOff(TNoDate)
+	)
+	if needDate {
+		if tf.anyOn(TYear) {
+			ye = p.num(4)
+			p.lit('-')
+		}
+		mo = time.Month(p.num(2))
+		p.lit('-')
+		dy = p.num(2)
+		if tf.allOff(TNoWeekday) {
+			p.lit(' ')
+			wd = p.take(2)
+		}
+		if needTime {
+			p.lit(' ')
+		}
+	}
+	if needTime {
+		ho = p.num(2)
+		p.lit(':')
+		mi = p.num(2)
+		p.lit(':')
+		sc = p.num(2)
+		switch {
+		case tf.anyOn(TMicros):
+			p.lit('.')
+			ns = p.num(6) * 1000
+		case tf.anyOn(TMillis):
+			p.lit('.')
+			ns = p.num(3) * 1000000
+		}
+		if tf.allOff(TUTC) {
+			loc = p.tzOff()
+		}
+	} else if tf.allOff(TUTC) {
+		p.lit(' ')
+		loc = p.tzOff()
+	}
+	switch {
+	case p.err != nil:
+	case p.s != "":
+		p.err = fmt.Errorf("trailing '%s'", p.s)
+	case ho > 23 || mi > 59 || sc > 59:
+		p.err = fmt.Errorf("invalid clock %02d:%02d:%02d", ho, mi, sc)
+	}
+	if p.err != nil {
+		return time.Time{}, fmt.Errorf("parse time '%s': %w", s, p.err)
+	}
+
+	if !needDate {
+		ye, mo, dy = ref.In(loc).Date()
+		return time.Date(ye, mo, dy, ho, mi, sc, ns, loc), nil
+	}
+	if tf.anyOn(TYear) {
+		t := time.Date(ye, mo, dy, ho, mi, sc, ns, loc)
+		switch {
+		case t.Month() != mo || t.Day() != dy:
+			return t, fmt.Errorf("parse time '%s': invalid date", s)
+		case wd != "" && t.Weekday().String()[:2] != wd:
+			return t, fmt.Errorf("parse time '%s': weekday %s does not match date", s, wd)
+		}
+		return t, nil
+	}
+	var (
+		res  time.Time
+		dist time.Duration = -1
+	)
+	for ye = ref.Year() - 1; ye <= ref.Year()+1; ye++ {
+		t := time.Date(ye, mo, dy, ho, mi, sc, ns, loc)
+		if t.Month() != mo || t.Day() != dy {
+			continue
+		}
+		if wd != "" && t.Weekday().String()[:2] != wd {
+			continue
+		}
+		d := t.Sub(ref)
+		if d < 0 {
+			d = -d
+		}
+		if dist < 0 || d < dist {
+			res, dist = t, d
+		}
+	}
+	if dist < 0 {
+		return res, fmt.Errorf("parse time '%s': no matching date near %s", s, ref)
+	}
+	return res, nil
+}
 
 const (
 	TUTC TimeFormat = 1 << iota
@@ -82,15 +183,93 @@ func (tf timeFormatter) AppendSllm(buf []byte) []byte {
 	return buf
 }
 
-func tzOff(buf []byte, sec int, p, n string) []byte {
-	sec = int(math.Round(float64(sec / (60 * 60))))
+type timeParser struct {
+	s   string
+	err error
+}
+
+// num reads exactly w digits. Further digits are left in p.s and are
+// rejected by the next step of the parser.
+func (p *timeParser) num(w int) (n int) {
+	if p.err != nil {
+		return 0
+	}
+	for i := 0; i < w; i++ {
+		if i >= len(p.s) || p.s[i] < '0' || p.s[i] > '9' {
+			p.err = fmt.Errorf("expect %d digits at '%s'", w, p.s)
+			return 0
+		}
+		n = 10*n + int(p.s[i]-'0')
+	}
+	p.s = p.s[w:]
+	return n
+}
+
+func (p *timeParser) lit(c byte) {
+	if p.err != nil {
+		return
+	}
+	if p.s == "" || p.s[0] != c {
+		p.err = fmt.Errorf("expect '%c' at '%s'", c, p.s)
+		return
+	}
+	p.s = p.s[1:]
+}
+
+func (p *timeParser) take(n int) (s string) {
+	if p.err != nil {
+		return ""
+	}
+	if len(p.s) < n {
+		p.err = fmt.Errorf("expect %d characters at '%s'", n, p.s)
+		return ""
+	}
+	s, p.s = p.s[:n], p.s[n:]
+	return s
+}
+
+func (p *timeParser) tzOff() *time.Location {
+	if p.err != nil {
+		return nil
+	}
+	var sign int
 	switch {
-	case sec < 0:
+	case p.s == "":
+	case p.s[0] == '+':
+		sign = 1
+	case p.s[0] == '-':
+		sign = -1
+	}
+	if sign == 0 {
+		p.err = fmt.Errorf("expect time zone offset at '%s'", p.s)
+		return nil
+	}
+	p.s = p.s[1:]
+	h, m := p.num(2), 0
+	if p.s != "" && p.s[0] == ':' {
+		p.s = p.s[1:]
+		m = p.num(2)
+	}
+	if p.err != nil {
+		return nil
+	}
+	return time.FixedZone("", sign*(h*60+m)*60)
+}
+
+// tzOff appends the zone offset sec as hours. Minutes are appended only if
+// not zero, e.g. "+05:30".
+func tzOff(buf []byte, sec int, p, n string) []byte {
+	if sec < 0 {
 		buf = append(buf, n...)
-		buf = uitoa(buf, -sec, 2)
-	default:
+		sec = -sec
+	} else {
 		buf = append(buf, p...)
-		buf = uitoa(buf, sec, 2)
+	}
+	mi := sec / 60
+	buf = uitoa(buf, mi/60, 2)
+	if mi %= 60; mi != 0 {
+		buf = append(buf, ':')
+		buf = uitoa(buf, mi, 2)
 	}
 	return buf
 }
@@ -64,3 +64,86 @@ func BenchmarkAppend_TimeFormat(b *testing.B) {
 		buf, _ = Append(buf[:0], "`its`", IdxArgs(t))
 	}
 }
+
+func ExampleTimeFormat_Parse() {
+	ref := time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)
+	t, err := TDefault.Parse("11-27 Mo 21:30:00+00", ref)
+	fmt.Println(t.Format(time.RFC3339), err)
+	_, err = TDefault.Parse("11-29 Mo 21:30:00+00", ref)
+	fmt.Println(err)
+	// Output:
+	// 2023-11-27T21:30:00Z <nil>
+	// parse time '11-29 Mo 21:30:00+00': no matching date near 2023-12-01 00:00:00 +0000 UTC
+}
+
+func TestTimeFormat_Parse(t *testing.T) {
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("West", -int((3 * time.Hour).Seconds())),
+		time.FixedZone("East", int((9 * time.Hour).Seconds())),
+		time.FixedZone("India", int((5*time.Hour + 30*time.Minute).Seconds())),
+		time.FixedZone("Newfoundland", -int((3*time.Hour + 30*time.Minute).Seconds())),
+	}
+	for _, tz := range zones {
+		ts := time.Date(2023, 05, 04, 21, 43, 1, 2003000, tz)
+		for tf := TimeFormat(0); tf < TMicros<<1; tf++ {
+			str := string(tf.Append(nil, ts))
+			pt, err := tf.Parse(str, ts)
+			if err != nil {
+				t.Errorf("%s %b: %s", tz, tf, err)
+				continue
+			}
+			if rts := string(tf.Append(nil, pt)); rts != str {
+				t.Errorf("%s %b: '%s' parsed as '%s'", tz, tf, str, rts)
+			}
+		}
+	}
+	t.Run("half hour zone", func(t *testing.T) {
+		ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("", 5*3600+1800))
+		tf := TYear | TMicros
+		str := string(tf.Append(nil, ts))
+		if str != "2024-03-01 Fr 12:00:00.000000+05:30" {
+			t.Errorf("unexpected format '%s'", str)
+		}
+		pt, err := tf.Parse(str, ts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pt.Equal(ts) {
+			t.Errorf("parsed %s, expected %s", pt.UTC(), ts.UTC())
+		}
+		if s := string(TUTC.Append(nil, ts)); s != "03-01 Fr 06:30:00" {
+			t.Errorf("unexpected UTC format '%s'", s)
+		}
+	})
+	t.Run("year from ref", func(t *testing.T) {
+		ref := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+		pt, err := (TUTC | TNoWeekday).Parse("12-31 23:59:59", ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if y := pt.Year(); y != 2023 {
+			t.Errorf("wrong year %d", y)
+		}
+	})
+	t.Run("errors", func(t *testing.T) {
+		for str, tf := range map[string]TimeFormat{
+			"2023-05-04 Fr 21:43:01":  TUTC | TYear,
+			"05-04 Th 21:43":          TUTC,
+			"05-04 Th 21:43:01 ":      TUTC,
+			"05-04 Th 21:43:01":       TDefault,
+			"2023-02-30 12:00:00":     TUTC | TYear | TNoWeekday,
+			"25:00:00":                TUTC | TNoDate,
+			"05-04 Th 21:43:01.1+00":  TMillis,
+			"05-04 Th 21:43:01x02:00": TUTC,
+			"05-04 Th 21:43:01.1234":  TUTC | TMillis,
+			"05-04 Th 21:43:001":      TUTC,
+			"12023-05-04 21:43:01":    TUTC | TYear | TNoWeekday,
+			"05-04 Th 21:43:01+010":   TDefault,
+		} {
+			if _, err := tf.Parse(str, time.Now()); err == nil {
+				t.Errorf("no error for '%s' with %b", str, tf)
+			}
+		}
+	})
+}
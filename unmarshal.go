@@ -0,0 +1,226 @@
+package sllm
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unmarshal parses the sllm message msg and assigns the arguments to the
+// fields of the struct pointed to by v. Parameters are mapped to fields the
+// same way as with [StructArgs]. It is a shorthand for using the zero value of
+// [Unmarshaler].
+func Unmarshal(msg string, v any) error {
+	var u Unmarshaler
+	return u.Unmarshal(msg, v)
+}
+
+// Unmarshaler converts the arguments of sllm messages into typed struct
+// fields. Supported field types are strings, bools, ints, uints, floats,
+// [time.Duration], [time.Time], [encoding.TextUnmarshaler], pointers to them
+// and slices of them. Repeated parameters are appended to slice fields, for
+// other fields the last argument wins. Parameters without a field are ignored.
+type Unmarshaler struct {
+	// TimeFormat is tried first to parse time.Time fields. If it fails,
+	// DefaultTimeFormat, the format of time.Time.String and RFC 3339 are
+	// tried.
+	TimeFormat TimeFormat
+	// TimeRef is the reference time for TimeFormat.Parse. If zero, the
+	// current time is used.
+	TimeRef time.Time
+}
+
+// FieldError reports an argument that could not be assigned to its field.
+type FieldError struct {
+	Name  string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("field '%s' value '%s': %s", e.Name, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// ArgValueError reports an argument error `name!(msg)` found in a message.
+type ArgValueError struct {
+	Name string
+	Msg  string
+}
+
+func (e *ArgValueError) Error() string {
+	return fmt.Sprintf("argument error '%s': %s", e.Name, e.Msg)
+}
+
+// Unmarshal parses msg with [Parse] and assigns the arguments to the fields
+// of the struct pointed to by v. Syntax errors of msg are returned
+// immediately. Otherwise all *FieldError and *ArgValueError are collected and
+// returned with [errors.Join].
+func (u *Unmarshaler) Unmarshal(msg string, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("unmarshal into non-pointer %T", v)
+	}
+	rv = rv.Elem()
+	if rv.Kind() != reflect.Struct {
+		return fmt.Errorf("unmarshal into %T: not a struct", v)
+	}
+	plan := structPlanOf(rv.Type())
+	var errs []error
+	err := Parse(msg, nil, func(name, value string, argErr bool) error {
+		f, ok := plan[name]
+		switch {
+		case !ok:
+			return nil
+		case argErr:
+			errs = append(errs, &ArgValueError{Name: name, Msg: value})
+			return nil
+		}
+		value = UnescString(value)
+		fv, err := f.settable(rv)
+		if err == nil {
+			err = u.assign(fv, value)
+		}
+		if err != nil {
+			errs = append(errs, &FieldError{Name: name, Value: value, Err: err})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return errors.Join(errs...)
+}
+
+func (f fieldPlan) settable(sv reflect.Value) (reflect.Value, error) {
+	for i, x := range f.index {
+		if i > 0 {
+			for sv.Kind() == reflect.Pointer {
+				if sv.IsNil() {
+					if !sv.CanSet() {
+						return sv, fmt.Errorf("cannot allocate %s", sv.Type())
+					}
+					sv.Set(reflect.New(sv.Type().Elem()))
+				}
+				sv = sv.Elem()
+			}
+		}
+		sv = sv.Field(x)
+	}
+	if !sv.CanSet() {
+		return sv, errors.New("cannot set field")
+	}
+	return sv, nil
+}
+
+var (
+	timeType            = reflect.TypeOf(time.Time{})
+	durationType        = reflect.TypeOf(time.Duration(0))
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+)
+
+func (u *Unmarshaler) assign(fv reflect.Value, s string) error {
+	switch fv.Type() {
+	case timeType:
+		t, err := u.parseTime(s)
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(t))
+		return nil
+	case durationType:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	if fv.Kind() == reflect.Pointer {
+		if fv.IsNil() {
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return u.assign(fv.Elem(), s)
+	}
+	if reflect.PointerTo(fv.Type()).Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(i)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		i, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(i)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(f)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() == reflect.Uint8 {
+			fv.SetBytes([]byte(s))
+			return nil
+		}
+		e := reflect.New(fv.Type().Elem()).Elem()
+		if err := u.assign(e, s); err != nil {
+			return err
+		}
+		fv.Set(reflect.Append(fv, e))
+	case reflect.Interface:
+		if fv.NumMethod() > 0 {
+			return fmt.Errorf("unsupported type %s", fv.Type())
+		}
+		fv.Set(reflect.ValueOf(s))
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
+
+const timeStringLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
+
+func (u *Unmarshaler) parseTime(s string) (time.Time, error) {
+	ref := u.TimeRef
+	if ref.IsZero() {
+		ref = time.Now()
+	}
+	t, err := u.TimeFormat.Parse(s, ref)
+	if err == nil {
+		return t, nil
+	}
+	if t, e := DefaultTimeFormat.Parse(s, ref); e == nil {
+		return t, nil
+	}
+	// Strip monotonic clock reading of time.Time.String
+	if i := strings.Index(s, " m="); i > 0 {
+		s = s[:i]
+	}
+	if t, e := time.Parse(timeStringLayout, s); e == nil {
+		return t, nil
+	}
+	if t, e := time.Parse(time.RFC3339Nano, s); e == nil {
+		return t, nil
+	}
+	return t, err
+}
@@ -0,0 +1,103 @@
+package sllm
+
+import (
+	"errors"
+	"fmt"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func ExampleUnmarshal() {
+	var cart struct {
+		Count int    `sllm:"count"`
+		Item  string `sllm:"item"`
+		User  string `sllm:"user"`
+	}
+	err := Unmarshal(
+		"added `count:7` ⨉ `item:Hat` to shopping cart by `user:John Doe`",
+		&cart,
+	)
+	fmt.Printf("%+v %v\n", cart, err)
+	// Output:
+	// {Count:7 Item:Hat User:John Doe} <nil>
+}
+
+func TestUnmarshal(t *testing.T) {
+	type inner struct {
+		ID int `sllm:"id"`
+	}
+	type target struct {
+		S   string        `sllm:"s"`
+		B   bool          `sllm:"b"`
+		I8  int8          `sllm:"i8"`
+		U   uint          `sllm:"u"`
+		F   float32       `sllm:"f"`
+		D   time.Duration `sllm:"d"`
+		T   time.Time     `sllm:"t"`
+		TS  time.Time     `sllm:"ts"`
+		IP  netip.Addr    `sllm:"ip"`
+		P   *int          `sllm:"p"`
+		L   []int         `sllm:"l"`
+		Bs  []byte        `sllm:"bs"`
+		A   any           `sllm:"a"`
+		Req *inner        `sllm:"req"`
+	}
+	at := time.Date(2023, 11, 27, 21, 30, 0, 0, time.UTC)
+	msg, err := String(
+		"`s` `b` `i8` `u` `f` `d` `t` `ts` `ip` `p` `l` `l` `bs` `a` `req.id` `other`",
+		IdxArgs("x`y", true, -8, 8, 1.5, 3*time.Second, TDefault.Fmt(at), at,
+			netip.MustParseAddr("127.0.0.1"), 4711, 1, 2, "bytes", "any", 3, "ignored",
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	u := Unmarshaler{TimeRef: at}
+	var v target
+	if err := u.Unmarshal(msg, &v); err != nil {
+		t.Fatal(err)
+	}
+	p := 4711
+	expect := target{
+		S: "x`y", B: true, I8: -8, U: 8, F: 1.5, D: 3 * time.Second,
+		T: at, TS: at, IP: netip.MustParseAddr("127.0.0.1"), P: &p,
+		L: []int{1, 2}, Bs: []byte("bytes"), A: "any", Req: &inner{ID: 3},
+	}
+	if !v.T.Equal(expect.T) || !v.TS.Equal(expect.TS) {
+		t.Errorf("wrong times %s, %s", v.T, v.TS)
+	}
+	v.T, v.TS = at, at
+	if !reflect.DeepEqual(v, expect) {
+		t.Errorf("unexpected result %+v", v)
+	}
+}
+
+func TestUnmarshal_errors(t *testing.T) {
+	var v struct {
+		I int `sllm:"i"`
+		J int `sllm:"j"`
+	}
+	err := Unmarshal("`i:x` and `j!(missing argument)` end", &v)
+	var ferr *FieldError
+	if !errors.As(err, &ferr) {
+		t.Fatalf("no field error in %v", err)
+	}
+	if ferr.Name != "i" || ferr.Value != "x" {
+		t.Errorf("unexpected field error %v", ferr)
+	}
+	var aerr *ArgValueError
+	if !errors.As(err, &aerr) {
+		t.Fatalf("no argument error in %v", err)
+	}
+	if aerr.Name != "j" || aerr.Msg != "missing argument" {
+		t.Errorf("unexpected argument error %v", aerr)
+	}
+	if err := Unmarshal("`i:1`", v); err == nil {
+		t.Error("no error for non-pointer")
+	}
+	if err := Unmarshal("`i:1", &v); err == nil {
+		t.Error("no syntax error")
+	}
+}
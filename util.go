@@ -7,28 +7,42 @@ import (
 	"io"
 )
 
+// FprintIdx works like Fprint with the positional arguments args. Format
+// specs are applied to the typed arguments, see [AppendValues].
 func FprintIdx(w io.Writer, tmpl string, args ...any) (int, error) {
-	return Fprint(w, tmpl, IdxArgs(args...))
+	return fprint(w, func(to []byte) ([]byte, error) {
+		return AppendValues(to, tmpl, IdxValues(args...))
+	})
 }
 
 func Fprint(w io.Writer, tmpl string, args ArgsFunc) (int, error) {
+	return fprint(w, func(to []byte) ([]byte, error) {
+		return Append(to, tmpl, args)
+	})
+}
+
+func fprint(w io.Writer, app func([]byte) ([]byte, error)) (int, error) {
 	if buf, ok := w.(*bytes.Buffer); ok {
-		tmp, err := Append(buf.Bytes(), tmpl, args)
+		tmp, err := app(buf.Bytes())
 		if err != nil {
 			return 0, err
 		}
 		buf.Reset()
 		return buf.Write(tmp)
 	}
-	tmp, err := Append(nil, tmpl, args)
+	tmp, err := app(nil)
 	if err != nil && !errors.Is(err, ArgErrors{}) {
 		return 0, err
 	}
 	return w.Write(tmp)
 }
 
+// StringIdx returns the message from template tmpl with the positional
+// arguments args. Format specs are applied to the typed arguments, see
+// [AppendValues].
 func StringIdx(tmpl string, args ...any) (string, error) {
-	return String(tmpl, IdxArgs(args...))
+	buf, err := AppendValues(nil, tmpl, IdxValues(args...))
+	return string(buf), err
 }
 
 func String(tmpl string, args ArgsFunc) (string, error) {
@@ -36,8 +50,13 @@ func String(tmpl string, args ArgsFunc) (string, error) {
 	return string(buf), err
 }
 
+// ErrorIdx returns an [*Err] that is rendered with the positional arguments
+// args. Unlike [NewErr], the message is rendered immediately, i.e. later
+// changes to the arguments do not change the message.
 func ErrorIdx(tmpl string, args ...any) error {
-	return Error(tmpl, IdxArgs(args...))
+	e := NewErr(tmpl, args...)
+	e.render()
+	return e
 }
 
 func Error(tmpl string, args ArgsFunc) error {
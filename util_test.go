@@ -12,3 +12,10 @@ func ExamplePrint_argError() {
 	// `argok:4711` but `notok!(missing argument 1 'notok')`
 	// <nil>
 }
+
+func ExampleUnescString() {
+	esc := EscString(nil, "tic`toc")
+	fmt.Println(string(esc), UnescString(string(esc)))
+	// Output:
+	// tic``toc tic`toc
+}